@@ -0,0 +1,178 @@
+package concurrency
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and creates Timer(s). It allows the time-based primitives in this package to be tested
+// deterministically by swapping the SystemClock for a FakeClock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a Timer that will send the current time on its channel after at least the given duration.
+	NewTimer(d time.Duration) Timer
+	// After waits for the given duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// Timer is a Clock agnostic version of time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing. Returns false if the Timer has already expired or been stopped.
+	Stop() bool
+	// Reset changes the Timer to expire after the given duration. Returns true if the Timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// SystemClock is the Clock that uses the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) NewTimer(d time.Duration) Timer         { return systemTimer{time.NewTimer(d)} }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type systemTimer struct{ *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time { return t.Timer.C }
+
+// clockOrSystem returns the given Clock, or the SystemClock if the given Clock is nil.
+func clockOrSystem(clock Clock) Clock {
+	if clock == nil {
+		return SystemClock
+	}
+	return clock
+}
+
+// FakeClock is a Clock whose time only moves forward when FakeClock.Advance is called. Timers created by a FakeClock
+// fire when the time is advanced past their deadline.
+type FakeClock struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock creates a FakeClock that starts at the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mutex)
+	return c
+}
+
+// Now returns the current time of the FakeClock.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// NewTimer creates a Timer that fires once the FakeClock has been advanced by at least the given duration.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	t.Reset(d)
+	return t
+}
+
+// After waits for the FakeClock to be advanced by the given duration and then sends the current time on the returned
+// channel.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time { return c.NewTimer(d).C() }
+
+// Advance moves the time of the FakeClock forward by the given duration, firing any timers whose deadlines have been
+// reached in deadline order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+// Timers returns the number of timers that are waiting to fire.
+func (c *FakeClock) Timers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil blocks until there are at least n timers that are waiting to fire. This is useful to ensure that a
+// goroutine is waiting on the FakeClock before advancing it.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// BlockUntilTimer blocks until there is a timer that is waiting to fire at the given time. This is useful to ensure
+// that a goroutine has reset a timer before advancing the FakeClock.
+func (c *FakeClock) BlockUntilTimer(deadline time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for {
+		for _, t := range c.timers {
+			if t.deadline.Equal(deadline) {
+				return
+			}
+		}
+		c.cond.Wait()
+	}
+}
+
+// fire sends the current time on the channel of every timer whose deadline has been reached. The mutex must be held.
+func (c *FakeClock) fire() {
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		return c.timers[i].deadline.Before(c.timers[j].deadline)
+	})
+
+	i := 0
+	for ; i < len(c.timers) && !c.timers[i].deadline.After(c.now); i++ {
+		t := c.timers[i]
+		select {
+		case t.c <- c.now:
+		default:
+		}
+	}
+	c.timers = c.timers[i:]
+}
+
+// remove removes the given timer from the waiting timers. Returns whether the timer was waiting. The mutex must be
+// held.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i, timer := range c.timers {
+		if timer == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	wasActive := t.clock.remove(t)
+	t.deadline = t.clock.now.Add(d)
+	t.clock.timers = append(t.clock.timers, t)
+	t.clock.fire()
+	t.clock.cond.Broadcast()
+	return wasActive
+}
@@ -1,51 +1,246 @@
 // Package concurrency contains data-structures and functions to do with concurrency and goroutines.
 package concurrency
 
-// InOut creates infinite channels that don't block when written to.
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what happens when a value is written to an Unbounded channel whose internal queue has reached
+// its soft cap (see UnboundedOptions.SoftCap).
+type OverflowPolicy int
+
+const (
+	// OverflowBlock stops reading from the In channel until a value has been read from the Out channel. This applies
+	// backpressure to writers, as writing to the In channel will now block.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest buffered value to make room for the newly written value. For channels
+	// created with NewPriorityUnbounded, the lowest priority buffered value is discarded instead.
+	OverflowDropOldest
+	// OverflowDropNewest discards the newly written value.
+	OverflowDropNewest
+	// OverflowError discards the newly written value and calls UnboundedOptions.OnOverflow with ErrOverflow.
+	OverflowError
+)
+
+func (op OverflowPolicy) String() string {
+	switch op {
+	case OverflowBlock:
+		return "Block"
+	case OverflowDropOldest:
+		return "DropOldest"
+	case OverflowDropNewest:
+		return "DropNewest"
+	case OverflowError:
+		return "Error"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", op)
+	}
+}
+
+// ErrOverflow is passed to UnboundedOptions.OnOverflow when a value is rejected under the OverflowError policy.
+var ErrOverflow = errors.New("unbounded channel queue has reached its soft cap")
+
+// UnboundedOptions configures an Unbounded channel created using NewUnboundedWithOptions. The zero value is a truly
+// unbounded channel.
+type UnboundedOptions[T any] struct {
+	// SoftCap is the maximum number of values that can be buffered in the internal queue before the Overflow policy is
+	// applied. If SoftCap <= 0 then the queue can grow forever.
+	SoftCap int
+	// Overflow is the OverflowPolicy that is applied once the queue has reached SoftCap values.
+	Overflow OverflowPolicy
+	// OnOverflow is called with each value that is discarded due to the Overflow policy. err is ErrOverflow when the
+	// policy is OverflowError, and nil otherwise. OnOverflow is called from the goroutine that moves values from In to
+	// Out so it should not block.
+	OnOverflow func(item T, err error)
+	// MinCapacity is the capacity that the internal structs.Deque starts at, and will never be compacted below. If
+	// MinCapacity <= 0 then a default capacity of 16 is used.
+	MinCapacity int
+}
+
+// full returns whether a queue of the given length has reached the SoftCap.
+func (o UnboundedOptions[T]) full(length int) bool {
+	return o.SoftCap > 0 && length >= o.SoftCap
+}
+
+func (o UnboundedOptions[T]) overflow(item T, err error) {
+	if o.OnOverflow != nil {
+		o.OnOverflow(item, err)
+	}
+}
+
+// Unbounded is an infinite channel that doesn't block when written to. Values written to the In channel are buffered
+// within an internal queue until they can be read from the Out channel. Values come out in the same order as they are
+// written.
+//
+// The internal queue is a ring buffer which grows when it is full and is compacted when it is mostly empty, so the
+// memory held by a backlog is released once it has been consumed. A soft cap and OverflowPolicy can be set using
+// NewUnboundedWithOptions to stop the queue from growing forever when readers stall.
+//
+// The Out channel is closed once the In channel has been closed and every buffered value has been read, or once the
+// context.Context given to NewUnboundedContext is cancelled.
+type Unbounded[T any] struct {
+	in     chan T
+	out    chan T
+	done   chan struct{}
+	ctx    context.Context
+	opts   UnboundedOptions[T]
+	length int64
+	// queue is only accessed by the pump goroutine
+	queue queue[T]
+
+	// drainMutex guards unread which contains the values still buffered when the pump goroutine exited
+	drainMutex sync.Mutex
+	unread     []T
+}
+
+// NewUnbounded creates a new Unbounded channel and starts the goroutine that moves values from its In channel to its
+// Out channel.
 //
 // This is from https://medium.com/capital-one-tech/building-an-unbounded-channel-in-go-789e175cd2cd.
-func InOut() (chan<- any, <-chan any) {
-	in := make(chan any)
-	out := make(chan any)
-
-	go func() {
-		var inQueue []any
-
-		// Temp function which returns the out channel to write to
-		// This is done to avoid writing nils to the out channel
-		outCh := func() chan any {
-			if len(inQueue) == 0 {
-				return nil
+func NewUnbounded[T any]() *Unbounded[T] {
+	return NewUnboundedWithOptions(UnboundedOptions[T]{})
+}
+
+// NewUnboundedWithOptions creates a new Unbounded channel that is configured using the given UnboundedOptions.
+func NewUnboundedWithOptions[T any](opts UnboundedOptions[T]) *Unbounded[T] {
+	return NewUnboundedContext(context.Background(), opts)
+}
+
+// NewUnboundedContext creates a new Unbounded channel, configured using the given UnboundedOptions, whose goroutine
+// stops when the given context.Context is cancelled.
+//
+// On cancellation the Out channel is closed without writing the values that are still buffered. These can be
+// retrieved using Unbounded.Drain. Writers should also select on ctx.Done() when writing to the In channel, as nothing
+// will read from the In channel after cancellation.
+func NewUnboundedContext[T any](ctx context.Context, opts UnboundedOptions[T]) *Unbounded[T] {
+	return newUnbounded[T](ctx, opts, newDequeQueue[T](opts.MinCapacity))
+}
+
+// newUnbounded creates a new Unbounded channel that buffers values using the given queue.
+func newUnbounded[T any](ctx context.Context, opts UnboundedOptions[T], q queue[T]) *Unbounded[T] {
+	u := &Unbounded[T]{
+		in:    make(chan T),
+		out:   make(chan T),
+		done:  make(chan struct{}),
+		ctx:   ctx,
+		opts:  opts,
+		queue: q,
+	}
+	go u.pump()
+	return u
+}
+
+// In returns the channel that values should be written to. Writing to this channel will never block, unless a soft
+// cap has been reached using the OverflowBlock policy. The channel should be closed once no more values will be
+// written.
+func (u *Unbounded[T]) In() chan<- T { return u.in }
+
+// Out returns the channel that values can be read from.
+func (u *Unbounded[T]) Out() <-chan T { return u.out }
+
+// Len returns the number of values that are currently buffered in the internal queue. That is, the number of values
+// that have been written to In but have not yet been read from Out.
+func (u *Unbounded[T]) Len() int { return int(atomic.LoadInt64(&u.length)) }
+
+// Done returns a channel that is closed once the internal goroutine has exited. This happens after the Out channel has
+// been closed.
+func (u *Unbounded[T]) Done() <-chan struct{} { return u.done }
+
+// Drain waits for the internal goroutine to exit, then returns the values that were still buffered at that point in
+// the order that they would have been read from Out. This will only return values when the Unbounded channel was
+// stopped by cancelling its context.Context. Subsequent calls return nil.
+func (u *Unbounded[T]) Drain() []T {
+	<-u.done
+	u.drainMutex.Lock()
+	defer u.drainMutex.Unlock()
+	unread := u.unread
+	u.unread = nil
+	return unread
+}
+
+func (u *Unbounded[T]) pump() {
+	in := u.in
+	inQueue := u.queue
+	defer func() {
+		close(u.out)
+		if inQueue.len() > 0 {
+			u.drainMutex.Lock()
+			u.unread = make([]T, 0, inQueue.len())
+			for inQueue.len() > 0 {
+				u.unread = append(u.unread, inQueue.pop())
 			}
-			return out
+			u.drainMutex.Unlock()
+			atomic.StoreInt64(&u.length, 0)
+		}
+		close(u.done)
+	}()
+
+	// Temp function which returns the in channel to read from
+	// This is done to stop reading from the in channel when we are applying backpressure
+	inCh := func() chan T {
+		if u.opts.Overflow == OverflowBlock && u.opts.full(inQueue.len()) {
+			return nil
 		}
+		return in
+	}
 
-		// Returns the head of the input queue if the queue is not empty otherwise it returns nil
-		curVal := func() any {
-			if len(inQueue) == 0 {
-				return nil
-			}
-			return inQueue[0]
+	// Temp function which returns the out channel to write to
+	// This is done to avoid writing zero values to the out channel
+	outCh := func() chan T {
+		if inQueue.len() == 0 {
+			return nil
 		}
+		return u.out
+	}
 
-		for len(inQueue) > 0 || in != nil {
-			select {
-			// Read from input channel if we can
-			case v, ok := <-in:
-				if !ok {
-					// If input channel is empty then we set input to a nil channel so we don't read anything more
-					in = nil
-				} else {
-					// We append the input to the queue to be written to out
-					inQueue = append(inQueue, v)
+	for inQueue.len() > 0 || in != nil {
+		select {
+		// Stop if the context has been cancelled
+		case <-u.ctx.Done():
+			return
+		// Read from input channel if we can
+		case v, ok := <-inCh():
+			if !ok {
+				// If input channel is empty then we set input to a nil channel so we don't read anything more
+				in = nil
+				continue
+			}
+
+			if u.opts.full(inQueue.len()) {
+				switch u.opts.Overflow {
+				case OverflowDropOldest:
+					u.opts.overflow(inQueue.evict(), nil)
+					atomic.AddInt64(&u.length, -1)
+				case OverflowDropNewest:
+					u.opts.overflow(v, nil)
+					continue
+				case OverflowError:
+					u.opts.overflow(v, ErrOverflow)
+					continue
 				}
-			// If there is a value in the queue to write to out then write
-			case outCh() <- curVal():
-				// We pop off the head of the queue
-				inQueue = inQueue[1:]
 			}
+
+			// We push the input to the queue to be written to out
+			inQueue.push(v)
+			atomic.AddInt64(&u.length, 1)
+		// If there is a value in the queue to write to out then write
+		case outCh() <- inQueue.peek():
+			// We pop off the head of the queue
+			inQueue.pop()
+			atomic.AddInt64(&u.length, -1)
 		}
-		close(out)
-	}()
-	return in, out
+	}
+}
+
+// InOut creates infinite channels that don't block when written to. It is shorthand for creating an Unbounded channel
+// and returning its In and Out channels.
+//
+// This is from https://medium.com/capital-one-tech/building-an-unbounded-channel-in-go-789e175cd2cd.
+func InOut[T any]() (chan<- T, <-chan T) {
+	u := NewUnbounded[T]()
+	return u.In(), u.Out()
 }
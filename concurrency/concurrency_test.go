@@ -0,0 +1,49 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+)
+
+// capacityQueue is a dequeQueue that records the greatest capacity that its Deque grew to.
+type capacityQueue[T any] struct {
+	*dequeQueue[T]
+	maxCap int
+}
+
+func (q *capacityQueue[T]) push(v T) {
+	q.dequeQueue.push(v)
+	if q.deque.Cap() > q.maxCap {
+		q.maxCap = q.deque.Cap()
+	}
+}
+
+func TestUnboundedCompaction(t *testing.T) {
+	const minCapacity = 2
+	q := &capacityQueue[int]{dequeQueue: newDequeQueue[int](minCapacity)}
+	u := newUnbounded[int](context.Background(), UnboundedOptions[int]{MinCapacity: minCapacity}, q)
+	for i := 0; i < 1000; i++ {
+		u.In() <- i
+	}
+	close(u.In())
+
+	expected := 0
+	for v := range u.Out() {
+		if v != expected {
+			t.Fatalf("Got: %d, expected: %d", v, expected)
+		}
+		expected++
+	}
+	// The queue is only accessed by the pump goroutine, so it can only be inspected once it has exited
+	<-u.Done()
+
+	if u.Len() != 0 {
+		t.Errorf("Got Len: %d, expected: %d", u.Len(), 0)
+	}
+	if q.maxCap < 1000 {
+		t.Errorf("Got max capacity: %d, expected at least: %d", q.maxCap, 1000)
+	}
+	if c := q.deque.Cap(); c > 2*minCapacity {
+		t.Errorf("Got capacity: %d after the backlog drained, expected at most: %d", c, 2*minCapacity)
+	}
+}
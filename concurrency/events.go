@@ -0,0 +1,175 @@
+package concurrency
+
+import (
+	"time"
+)
+
+// stopTimer stops the given Timer and drains its channel so that it can be safely reset.
+func stopTimer(t Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C():
+		default:
+		}
+	}
+}
+
+// resetTimer returns a Timer that will fire after the given duration. If the given Timer is nil then a new Timer is
+// created using the given Clock, otherwise the given Timer is stopped and reset.
+func resetTimer(timer Timer, clock Clock, d time.Duration) Timer {
+	if timer == nil {
+		return clock.NewTimer(d)
+	}
+	stopTimer(timer)
+	timer.Reset(d)
+	return timer
+}
+
+// Debounce creates a channel which emits the latest value read from the given channel only once no other values have
+// been read for the given duration. Each burst of values is therefore collapsed into its last value. When the given
+// channel is closed, any pending value is emitted immediately before the returned channel is closed.
+func Debounce[T any](in <-chan T, d time.Duration) <-chan T {
+	return DebounceWithClock(in, d, SystemClock)
+}
+
+// DebounceWithClock works in the same way as Debounce but uses the given Clock.
+func DebounceWithClock[T any](in <-chan T, d time.Duration, clock Clock) <-chan T {
+	clock = clockOrSystem(clock)
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var timer Timer
+		// timeout is only non-nil whilst there is a pending value
+		var timeout <-chan time.Time
+		var pending T
+
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if timeout != nil {
+						timer.Stop()
+						out <- pending
+					}
+					return
+				}
+
+				pending = v
+				timer = resetTimer(timer, clock, d)
+				timeout = timer.C()
+			case <-timeout:
+				timeout = nil
+				out <- pending
+			}
+		}
+	}()
+	return out
+}
+
+// Throttle creates a channel which emits the first value read from the given channel, then drops any values that are
+// read within the given duration after it. Once the duration has elapsed, the next value read is emitted and the cycle
+// repeats.
+func Throttle[T any](in <-chan T, d time.Duration) <-chan T {
+	return ThrottleWithClock(in, d, SystemClock)
+}
+
+// ThrottleWithClock works in the same way as Throttle but uses the given Clock.
+func ThrottleWithClock[T any](in <-chan T, d time.Duration, clock Clock) <-chan T {
+	clock = clockOrSystem(clock)
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var timer Timer
+		// cooldown is only non-nil whilst values are being dropped
+		var cooldown <-chan time.Time
+
+		for {
+			// Prioritise the end of a cooldown over reading new values
+			select {
+			case <-cooldown:
+				cooldown = nil
+			default:
+			}
+
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if timer != nil {
+						timer.Stop()
+					}
+					return
+				}
+
+				if cooldown != nil {
+					continue
+				}
+				timer = resetTimer(timer, clock, d)
+				cooldown = timer.C()
+				out <- v
+			case <-cooldown:
+				cooldown = nil
+			}
+		}
+	}()
+	return out
+}
+
+// Coalesce creates a channel which collects the values read from the given channel into windows of the given duration.
+// A window starts when a value is read, and when it ends the values within it are emitted as a slice. Values with the
+// same key, according to the given key function, are collapsed so that only the latest value for each key is emitted.
+// Values are emitted in the order that their keys were first seen within the window. When the given channel is closed,
+// any pending window is emitted immediately before the returned channel is closed.
+func Coalesce[T any, K comparable](in <-chan T, key func(value T) K, window time.Duration) <-chan []T {
+	return CoalesceWithClock(in, key, window, SystemClock)
+}
+
+// CoalesceWithClock works in the same way as Coalesce but uses the given Clock.
+func CoalesceWithClock[T any, K comparable](in <-chan T, key func(value T) K, window time.Duration, clock Clock) <-chan []T {
+	clock = clockOrSystem(clock)
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var timer Timer
+		var timeout <-chan time.Time
+		batch := make([]T, 0)
+		indices := make(map[K]int)
+
+		flush := func() {
+			timeout = nil
+			if len(batch) > 0 {
+				out <- batch
+				batch = make([]T, 0)
+				indices = make(map[K]int)
+			}
+		}
+
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if timeout != nil {
+						timer.Stop()
+					}
+					flush()
+					return
+				}
+
+				if len(batch) == 0 {
+					timer = resetTimer(timer, clock, window)
+					timeout = timer.C()
+				}
+
+				k := key(v)
+				if i, ok := indices[k]; ok {
+					batch[i] = v
+				} else {
+					indices[k] = len(batch)
+					batch = append(batch, v)
+				}
+			case <-timeout:
+				flush()
+			}
+		}
+	}()
+	return out
+}
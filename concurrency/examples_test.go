@@ -1,8 +1,12 @@
 package concurrency
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 )
 
 // Sends values 0 through 9 into "in" channel and makes sure that they all come out in the correct order from the
@@ -10,19 +14,18 @@ import (
 //
 // Example and implementation are from: https://medium.com/capital-one-tech/building-an-unbounded-channel-in-go-789e175cd2cd.
 func ExampleInOut() {
-	in, out := InOut()
+	in, out := InOut[int]()
 	lastVal := -1
 	var wg sync.WaitGroup
 	wg.Add(1)
 
 	go func() {
 		for v := range out {
-			vi := v.(int)
-			fmt.Println("Reading:", vi)
-			if lastVal+1 != vi {
+			fmt.Println("Reading:", v)
+			if lastVal+1 != v {
 				panic("sequence is out of order")
 			}
-			lastVal = vi
+			lastVal = v
 		}
 		wg.Done()
 		fmt.Println("Finished reading!")
@@ -64,3 +67,448 @@ func ExampleInOut() {
 	// Reading: 9
 	// Finished reading!
 }
+
+// Creates an Unbounded channel of strings, writes some values to it without reading, and then reads them all back
+// whilst checking how many values are still buffered.
+func ExampleUnbounded() {
+	u := NewUnbounded[string]()
+	for _, s := range []string{"a", "b", "c"} {
+		u.In() <- s
+	}
+	close(u.In())
+
+	for s := range u.Out() {
+		fmt.Println("Read:", s)
+	}
+	fmt.Println("Buffered:", u.Len())
+	// Output:
+	// Read: a
+	// Read: b
+	// Read: c
+	// Buffered: 0
+}
+
+// Creates an Unbounded channel that can only buffer 3 values, and that will drop the oldest buffered value to make
+// room for new values once it is full.
+func ExampleNewUnboundedWithOptions() {
+	u := NewUnboundedWithOptions(UnboundedOptions[int]{
+		SoftCap:  3,
+		Overflow: OverflowDropOldest,
+		OnOverflow: func(item int, err error) {
+			fmt.Println("Dropped:", item)
+		},
+	})
+
+	for i := 0; i < 5; i++ {
+		u.In() <- i
+	}
+	close(u.In())
+
+	for v := range u.Out() {
+		fmt.Println("Read:", v)
+	}
+	// Output:
+	// Dropped: 0
+	// Dropped: 1
+	// Read: 2
+	// Read: 3
+	// Read: 4
+}
+
+// Creates an Unbounded channel that is stopped by cancelling its context.Context before all the values written to it
+// have been read. The unread values are then retrieved using Drain.
+func ExampleNewUnboundedContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := NewUnboundedContext(ctx, UnboundedOptions[int]{})
+
+	for i := 0; i < 5; i++ {
+		u.In() <- i
+	}
+	fmt.Println("Read:", <-u.Out())
+
+	cancel()
+	<-u.Done()
+	fmt.Println("Unread:", u.Drain())
+
+	_, ok := <-u.Out()
+	fmt.Println("Out open:", ok)
+	// Output:
+	// Read: 0
+	// Unread: [1 2 3 4]
+	// Out open: false
+}
+
+// Squares integers using a Pool of 3 workers, streaming the results in the order that they were submitted.
+func ExamplePool() {
+	pool := NewPool(PoolOptions{Workers: 3, Unbounded: true, Results: ResultsOrdered}, func(ctx context.Context, in int) (int, error) {
+		if in < 0 {
+			return 0, fmt.Errorf("%d is negative", in)
+		}
+		return in * in, nil
+	})
+
+	futures := make([]*Future[int], 0)
+	for _, in := range []int{1, 2, -3, 4} {
+		future, _ := pool.Submit(in)
+		futures = append(futures, future)
+	}
+	if err := pool.Shutdown(context.Background()); err != nil {
+		panic(err)
+	}
+
+	for result := range pool.Results() {
+		fmt.Println(result.Index, result.Value, result.Err)
+	}
+
+	value, err := futures[3].Wait()
+	fmt.Println("Future:", value, err)
+	// Output:
+	// 0 1 <nil>
+	// 1 4 <nil>
+	// 2 0 -3 is negative
+	// 3 16 <nil>
+	// Future: 16 <nil>
+}
+
+// Builds a Pipeline which squares the numbers 1 to 10, keeps the even squares, and then groups them into batches of 2.
+func ExamplePipeline() {
+	p := NewPipeline(context.Background(), PipelineOptions{Unbounded: true})
+	numbers := Source(p, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	squares := Map(p, numbers, func(ctx context.Context, value int) (int, error) {
+		return value * value, nil
+	})
+	evens := Filter(p, squares, func(ctx context.Context, value int) (bool, error) {
+		return value%2 == 0, nil
+	})
+
+	for batch := range Batch(p, evens, 2, time.Second) {
+		fmt.Println(batch)
+	}
+
+	if err := p.Wait(); err != nil {
+		panic(err)
+	}
+	// Output:
+	// [4 16]
+	// [36 64]
+	// [100]
+}
+
+// Errors returned by any stage cancel the whole Pipeline, and are returned by Pipeline.Wait.
+func ExamplePipeline_error() {
+	p := NewPipeline(context.Background(), PipelineOptions{})
+	numbers := Map(p, Source(p, 1, 2, 3), func(ctx context.Context, value int) (int, error) {
+		if value == 2 {
+			return 0, fmt.Errorf("cannot process %d", value)
+		}
+		return value, nil
+	})
+
+	for v := range numbers {
+		fmt.Println(v)
+	}
+	fmt.Println(p.Wait())
+	// Output:
+	// 1
+	// cannot process 2
+}
+
+// Fans the numbers 1 to 6 out to 3 workers that double them, then fans the results back in.
+func ExampleFanOut() {
+	p := NewPipeline(context.Background(), PipelineOptions{})
+	workers := FanOut(p, Source(p, 1, 2, 3, 4, 5, 6), 3)
+	for i, worker := range workers {
+		workers[i] = Map(p, worker, func(ctx context.Context, value int) (int, error) {
+			return value * 2, nil
+		})
+	}
+
+	doubled := make([]int, 0)
+	for v := range FanIn(p, workers...) {
+		doubled = append(doubled, v)
+	}
+	sort.Ints(doubled)
+	fmt.Println(doubled, p.Wait())
+	// Output:
+	// [2 4 6 8 10 12] <nil>
+}
+
+// Merges two sorted streams into one sorted stream.
+func ExampleMerge() {
+	p := NewPipeline(context.Background(), PipelineOptions{})
+	merged := Merge(p, func(a, b int) bool { return a < b }, Source(p, 1, 4, 5, 9), Source(p, 2, 3, 10))
+	for v := range merged {
+		fmt.Print(v, " ")
+	}
+	fmt.Println(p.Wait())
+	// Output:
+	// 1 2 3 4 5 9 10 <nil>
+}
+
+// Copies a stream to two channels.
+func ExampleTee() {
+	p := NewPipeline(context.Background(), PipelineOptions{Unbounded: true})
+	outs := Tee(p, Source(p, "a", "b", "c"), 2)
+	for i, out := range outs {
+		for v := range out {
+			fmt.Println(i, v)
+		}
+	}
+	fmt.Println(p.Wait())
+	// Output:
+	// 0 a
+	// 0 b
+	// 0 c
+	// 1 a
+	// 1 b
+	// 1 c
+	// <nil>
+}
+
+// Writes jobs with different priorities to a priority channel before reading them. Jobs with higher priorities are
+// read first, and jobs with the same priority are read in the order that they were written.
+func ExamplePriorityItemInOut() {
+	in, out := PriorityItemInOut[string]()
+	in <- PriorityItem[string]{Value: "tidy desk", Priority: 1}
+	in <- PriorityItem[string]{Value: "fix outage", Priority: 10}
+	in <- PriorityItem[string]{Value: "reply to emails", Priority: 5}
+	in <- PriorityItem[string]{Value: "water plants", Priority: 1}
+	in <- PriorityItem[string]{Value: "review PR", Priority: 5}
+	close(in)
+
+	for item := range out {
+		fmt.Println(item.Priority, item.Value)
+	}
+	// Output:
+	// 10 fix outage
+	// 5 reply to emails
+	// 5 review PR
+	// 1 tidy desk
+	// 1 water plants
+}
+
+// Creates a priority channel of strings where shorter strings have a higher priority.
+func ExamplePriorityInOut() {
+	in, out := PriorityInOut(func(a, b string) bool { return len(a) < len(b) })
+	for _, s := range []string{"ccc", "a", "dddd", "bb"} {
+		in <- s
+	}
+	close(in)
+
+	for s := range out {
+		fmt.Println(s)
+	}
+	// Output:
+	// a
+	// bb
+	// ccc
+	// dddd
+}
+
+// Creates a token bucket Limiter that allows 1 event per second with bursts of up to 2 events. A FakeClock is used so
+// that time only moves when we advance it.
+func ExampleLimiter() {
+	clock := NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := NewLimiterWithClock(1, 2, clock)
+
+	fmt.Println("Allow:", limiter.Allow(), limiter.Allow(), limiter.Allow())
+	clock.Advance(500 * time.Millisecond)
+	fmt.Println("Allow after 0.5s:", limiter.Allow())
+	clock.Advance(500 * time.Millisecond)
+	fmt.Println("Allow after 1s:", limiter.Allow())
+
+	// Reservations take a token even if there are none, so the caller will need to wait
+	r := limiter.Reserve()
+	fmt.Println("Reserve:", r.OK(), r.Delay())
+	r.Cancel()
+	fmt.Println("Tokens after cancel:", limiter.Tokens())
+	// Output:
+	// Allow: true true false
+	// Allow after 0.5s: false
+	// Allow after 1s: true
+	// Reserve: true 1s
+	// Tokens after cancel: 0
+}
+
+// Creates a SlidingWindow that allows 2 events within any 10 second window.
+func ExampleSlidingWindow() {
+	clock := NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	window := NewSlidingWindowWithClock(2, 10*time.Second, clock)
+
+	fmt.Println("0s:", window.Allow())
+	clock.Advance(4 * time.Second)
+	fmt.Println("4s:", window.Allow(), window.Allow())
+	clock.Advance(6 * time.Second)
+	fmt.Println("10s:", window.Allow(), window.Allow())
+	// Output:
+	// 0s: true
+	// 4s: true false
+	// 10s: true false
+}
+
+// Runs a Group of goroutines, 2 at a time, and collects all of their errors into a MultiError.
+func ExampleGroup() {
+	errNotFound := errors.New("not found")
+	g, _ := NewGroup(context.Background(), GroupOptions{Limit: 2})
+	for _, name := range []string{"a", "b", "c", "d"} {
+		name := name
+		g.Go(func(ctx context.Context) error {
+			switch name {
+			case "b":
+				return fmt.Errorf("could not find %q: %w", name, errNotFound)
+			case "d":
+				panic("oh no")
+			}
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	var multi *MultiError
+	var panicErr *PanicError
+	errors.As(err, &multi)
+	errors.As(err, &panicErr)
+	fmt.Println("Errors:", len(multi.Errors))
+	fmt.Println("Is not found:", errors.Is(err, errNotFound))
+	fmt.Println("Panic:", panicErr.Value)
+	// Output:
+	// Errors: 2
+	// Is not found: true
+	// Panic: oh no
+}
+
+// Debounces a burst of search queries so that only the final query is emitted once the user has stopped typing for a
+// second. A FakeClock is used so that no real time passes.
+func ExampleDebounceWithClock() {
+	clock := NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	in := make(chan string)
+	out := DebounceWithClock(in, time.Second, clock)
+
+	// Each key is pressed 100ms after the last
+	for _, query := range []string{"g", "go", "gop", "goph"} {
+		in <- query
+		// Wait for the debouncer to reset its timer before moving time along
+		clock.BlockUntilTimer(clock.Now().Add(time.Second))
+		clock.Advance(100 * time.Millisecond)
+	}
+	clock.Advance(time.Second)
+	fmt.Println(<-out)
+
+	// Pending queries are emitted as soon as the input channel is closed
+	in <- "gopher"
+	close(in)
+	fmt.Println(<-out)
+	// Output:
+	// goph
+	// gopher
+}
+
+// Throttles a stream of clicks so that only one click per second is emitted.
+func ExampleThrottleWithClock() {
+	clock := NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	in := make(chan int)
+	out := ThrottleWithClock(in, time.Second, clock)
+
+	in <- 1
+	fmt.Println(<-out)
+	// These are dropped as they are within a second of the first click
+	in <- 2
+	in <- 3
+
+	// Wait for the throttle's cooldown timer before moving time along
+	clock.BlockUntilTimer(clock.Now().Add(time.Second))
+	clock.Advance(time.Second)
+	in <- 4
+	fmt.Println(<-out)
+	close(in)
+	// Output:
+	// 1
+	// 4
+}
+
+// Coalesces file system events that happen within 500ms of each other so that only the latest event for each file is
+// emitted.
+func ExampleCoalesceWithClock() {
+	type event struct {
+		file string
+		op   string
+	}
+
+	clock := NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	in := make(chan event)
+	out := CoalesceWithClock(in, func(e event) string { return e.file }, 500*time.Millisecond, clock)
+
+	in <- event{"a.txt", "create"}
+	// Wait for the window to start before moving time along
+	clock.BlockUntil(1)
+	in <- event{"b.txt", "create"}
+	in <- event{"a.txt", "write"}
+	clock.Advance(500 * time.Millisecond)
+	fmt.Println(<-out)
+
+	in <- event{"b.txt", "remove"}
+	close(in)
+	fmt.Println(<-out)
+	// Output:
+	// [{a.txt write} {b.txt create}]
+	// [{b.txt remove}]
+}
+
+// De-duplicates concurrent requests for the same user so that the user is only looked up once whilst a lookup is in
+// flight.
+func ExampleSingleFlight() {
+	var sf SingleFlight[int, string]
+	lookup := func() (string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "Gopher", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, _ = sf.Do(1, lookup)
+		}(i)
+	}
+	wg.Wait()
+	fmt.Println("Results:", results)
+	// Output:
+	// Results: [Gopher Gopher Gopher]
+}
+
+// Memoizes an expensive function with a TTL and a maximum size, then prints the statistics of the cache.
+func ExampleMemoize() {
+	clock := NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	square := Memoize(func(key int) (int, error) {
+		fmt.Println("Computing", key)
+		return key * key, nil
+	}, MemoOptions{TTL: time.Minute, MaxSize: 2, Clock: clock})
+
+	for _, key := range []int{2, 2, 3, 4, 2} {
+		val, _ := square.Get(key)
+		fmt.Println(key, "=", val)
+	}
+
+	// After a minute all the cached values expire
+	clock.Advance(time.Minute)
+	square.Get(4)
+
+	stats := square.Stats()
+	fmt.Printf("%+v\n", stats)
+	fmt.Printf("Hit ratio: %.2f\n", stats.HitRatio())
+	// Output:
+	// Computing 2
+	// 2 = 4
+	// 2 = 4
+	// Computing 3
+	// 3 = 9
+	// Computing 4
+	// 4 = 16
+	// Computing 2
+	// 2 = 4
+	// Computing 4
+	// {Hits:1 Misses:5 Shared:0 Evictions:2 Expirations:1 Size:2}
+	// Hit ratio: 0.17
+}
@@ -0,0 +1,154 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MultiError is an error which contains multiple errors. errors.Is and errors.As will check each of the contained
+// errors.
+type MultiError struct {
+	Errors []error
+}
+
+func (me *MultiError) Error() string {
+	if len(me.Errors) == 1 {
+		return me.Errors[0].Error()
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%d errors occurred:", len(me.Errors)))
+	for _, err := range me.Errors {
+		b.WriteString("\n\t* ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap returns the contained errors.
+func (me *MultiError) Unwrap() []error { return me.Errors }
+
+// Is returns true if errors.Is returns true for any of the contained errors.
+func (me *MultiError) Is(target error) bool {
+	for _, err := range me.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the contained errors that errors.As matches to the given target.
+func (me *MultiError) As(target any) bool {
+	for _, err := range me.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupOptions configures a Group created using NewGroup.
+type GroupOptions struct {
+	// Limit is the maximum number of goroutines that can run at once. If Limit <= 0 then there is no limit.
+	Limit int
+	// CancelOnError cancels the Group's context.Context as soon as any goroutine returns an error or panics.
+	CancelOnError bool
+}
+
+// Group runs a collection of goroutines that work on subtasks of the same overall task, and collects all of their
+// errors.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	opts   GroupOptions
+	wg     sync.WaitGroup
+	sem    chan struct{}
+
+	// errMutex guards errs
+	errMutex sync.Mutex
+	errs     []error
+}
+
+// NewGroup creates a new Group configured using the given GroupOptions. The returned context.Context is derived from
+// the given context.Context and is passed to each goroutine. It is cancelled when Group.Wait returns, or when the first
+// error occurs if GroupOptions.CancelOnError is set.
+func NewGroup(ctx context.Context, opts GroupOptions) (*Group, context.Context) {
+	g := &Group{opts: opts}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	if opts.Limit > 0 {
+		g.sem = make(chan struct{}, opts.Limit)
+	}
+	return g, g.ctx
+}
+
+// Go calls the given function in a new goroutine. If GroupOptions.Limit goroutines are already running then Go blocks
+// until one of them has finished. Panics within the function are recovered and collected as a PanicError.
+func (g *Group) Go(fun func(ctx context.Context) error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.start(fun)
+}
+
+// TryGo calls the given function in a new goroutine only if doing so would not exceed GroupOptions.Limit. Returns
+// whether the goroutine was started.
+func (g *Group) TryGo(fun func(ctx context.Context) error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	g.start(fun)
+	return true
+}
+
+func (g *Group) start(fun func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if g.sem != nil {
+				<-g.sem
+			}
+			g.wg.Done()
+		}()
+
+		if err := g.run(fun); err != nil {
+			g.errMutex.Lock()
+			g.errs = append(g.errs, err)
+			g.errMutex.Unlock()
+			if g.opts.CancelOnError {
+				g.cancel()
+			}
+		}
+	}()
+}
+
+// run calls the given function, converting any panics into a PanicError.
+func (g *Group) run(fun func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+	return fun(g.ctx)
+}
+
+// Wait blocks until all goroutines started by Go and TryGo have returned. If any of them returned an error, or
+// panicked, then a MultiError containing every error, in the order that they occurred, is returned.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+
+	g.errMutex.Lock()
+	defer g.errMutex.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return &MultiError{Errors: append([]error(nil), g.errs...)}
+}
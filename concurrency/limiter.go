@@ -0,0 +1,317 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"github.com/andygello555/gotils/v2/structs"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is returned by RateLimiter.Wait when the RateLimiter will never be able to allow an event.
+var ErrLimitExceeded = errors.New("rate limiter can never allow an event")
+
+// RateLimiter is implemented by the rate limiters within this package.
+type RateLimiter interface {
+	// Allow reports whether an event can happen now. If it returns true then the event is counted against the limit.
+	Allow() bool
+	// Wait blocks until an event can happen, or until the given context.Context is done.
+	Wait(ctx context.Context) error
+}
+
+// Inf is the rate of a Limiter that allows all events.
+var Inf = math.Inf(1)
+
+// Every converts the minimum interval between events into a rate that can be given to NewLimiter.
+func Every(interval time.Duration) float64 {
+	if interval <= 0 {
+		return Inf
+	}
+	return 1 / interval.Seconds()
+}
+
+// Limiter is a token bucket RateLimiter. The bucket holds up to burst tokens and is refilled at rate tokens per
+// second. Each event takes one token from the bucket.
+type Limiter struct {
+	mutex  sync.Mutex
+	clock  Clock
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a new Limiter that allows events at up to the given rate per second, with bursts of up to burst
+// events. The bucket starts full.
+func NewLimiter(rate float64, burst int) *Limiter {
+	return NewLimiterWithClock(rate, burst, SystemClock)
+}
+
+// NewLimiterWithClock creates a new Limiter in the same way as NewLimiter, but uses the given Clock.
+func NewLimiterWithClock(rate float64, burst int, clock Clock) *Limiter {
+	clock = clockOrSystem(clock)
+	return &Limiter{
+		clock:  clock,
+		rate:   rate,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   clock.Now(),
+	}
+}
+
+// Rate returns the number of events that are allowed per second.
+func (l *Limiter) Rate() float64 { return l.rate }
+
+// Burst returns the maximum number of events that are allowed at once.
+func (l *Limiter) Burst() int { return l.burst }
+
+// Tokens returns the number of tokens that are currently in the bucket. This can be negative when there are
+// outstanding Reservation(s).
+func (l *Limiter) Tokens() float64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.advance(l.clock.Now())
+	return l.tokens
+}
+
+// advance refills the bucket with the tokens that have been generated since the last call. The mutex must be held.
+func (l *Limiter) advance(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 && !math.IsInf(l.rate, 1) {
+		l.tokens = math.Min(l.tokens+elapsed.Seconds()*l.rate, float64(l.burst))
+	}
+	l.last = now
+}
+
+// Allow reports whether an event can happen now. If it returns true then a token is taken from the bucket.
+func (l *Limiter) Allow() bool {
+	if math.IsInf(l.rate, 1) {
+		return true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.advance(l.clock.Now())
+	if l.tokens >= 1 {
+		l.tokens--
+		return true
+	}
+	return false
+}
+
+// Reserve takes a token from the bucket, even if the bucket is empty, and returns a Reservation which says how long
+// the caller must wait before the event can happen. Reservation.Cancel should be called if the event will not happen.
+func (l *Limiter) Reserve() *Reservation {
+	now := l.clock.Now()
+	if math.IsInf(l.rate, 1) {
+		return &Reservation{ok: true, limiter: l, timeToAct: now}
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.advance(now)
+	if l.burst <= 0 || (l.rate <= 0 && l.tokens < 1) {
+		return &Reservation{limiter: l, timeToAct: now}
+	}
+
+	l.tokens--
+	r := &Reservation{ok: true, limiter: l, timeToAct: now}
+	if l.tokens < 0 {
+		r.timeToAct = now.Add(time.Duration(-l.tokens / l.rate * float64(time.Second)))
+	}
+	return r
+}
+
+// Wait blocks until a token can be taken from the bucket. If the given context.Context is done first then the
+// context's error is returned, and the token is returned to the bucket. ErrLimitExceeded is returned if the Limiter
+// can never allow an event.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r := l.Reserve()
+	if !r.OK() {
+		return ErrLimitExceeded
+	}
+
+	delay := r.Delay()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := l.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// Reservation is a token that has been taken from a Limiter's bucket by Limiter.Reserve.
+type Reservation struct {
+	ok        bool
+	limiter   *Limiter
+	timeToAct time.Time
+	cancelled bool
+}
+
+// OK returns whether the Limiter can allow the event. If false then the Reservation holds no tokens, and Delay will
+// return 0.
+func (r *Reservation) OK() bool { return r.ok }
+
+// Delay returns how long the caller must wait before the reserved event can happen.
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return 0
+	}
+	if delay := r.timeToAct.Sub(r.limiter.clock.Now()); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// Cancel returns the reserved token to the Limiter's bucket, if the reserved event has not happened yet.
+func (r *Reservation) Cancel() {
+	if !r.ok || math.IsInf(r.limiter.rate, 1) {
+		return
+	}
+
+	r.limiter.mutex.Lock()
+	defer r.limiter.mutex.Unlock()
+	now := r.limiter.clock.Now()
+	if r.cancelled || !r.timeToAct.After(now) {
+		return
+	}
+	r.cancelled = true
+	r.limiter.advance(now)
+	r.limiter.tokens = math.Min(r.limiter.tokens+1, float64(r.limiter.burst))
+}
+
+// SlidingWindow is a RateLimiter that allows up to limit events within any window of time.
+type SlidingWindow struct {
+	mutex  sync.Mutex
+	clock  Clock
+	limit  int
+	window time.Duration
+	// events holds the times of the events within the current window, oldest first
+	events *structs.RingBuffer[time.Time]
+}
+
+// NewSlidingWindow creates a new SlidingWindow that allows up to limit events within any window of the given duration.
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return NewSlidingWindowWithClock(limit, window, SystemClock)
+}
+
+// NewSlidingWindowWithClock creates a new SlidingWindow in the same way as NewSlidingWindow, but uses the given Clock.
+func NewSlidingWindowWithClock(limit int, window time.Duration, clock Clock) *SlidingWindow {
+	capacity := limit
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &SlidingWindow{
+		clock:  clockOrSystem(clock),
+		limit:  limit,
+		window: window,
+		events: structs.NewRingBuffer[time.Time](capacity, false),
+	}
+}
+
+// evict removes the events that have fallen out of the window ending at the given time. The mutex must be held.
+func (sw *SlidingWindow) evict(now time.Time) {
+	for oldest, ok := sw.events.Front(); ok && !oldest.Add(sw.window).After(now); oldest, ok = sw.events.Front() {
+		sw.events.PopFront()
+	}
+}
+
+// reserve records an event at the current time if there is room within the window. Otherwise, it returns how long
+// until there will be room. The mutex must be held.
+func (sw *SlidingWindow) reserve() (ok bool, wait time.Duration) {
+	now := sw.clock.Now()
+	sw.evict(now)
+	if sw.events.Len() < sw.limit {
+		_ = sw.events.PushBack(now)
+		return true, 0
+	}
+	oldest, _ := sw.events.Front()
+	return false, oldest.Add(sw.window).Sub(now)
+}
+
+// Len returns the number of events within the current window.
+func (sw *SlidingWindow) Len() int {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	sw.evict(sw.clock.Now())
+	return sw.events.Len()
+}
+
+// Allow reports whether an event can happen now. If it returns true then the event is recorded within the window.
+func (sw *SlidingWindow) Allow() bool {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	ok, _ := sw.reserve()
+	return ok
+}
+
+// Wait blocks until an event can happen within the window, or until the given context.Context is done. ErrLimitExceeded
+// is returned if the SlidingWindow can never allow an event.
+func (sw *SlidingWindow) Wait(ctx context.Context) error {
+	if sw.limit <= 0 {
+		return ErrLimitExceeded
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		sw.mutex.Lock()
+		ok, wait := sw.reserve()
+		sw.mutex.Unlock()
+		if ok {
+			return nil
+		}
+
+		timer := sw.clock.NewTimer(wait)
+		select {
+		case <-timer.C():
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// RateLimited creates a channel which emits the values read from the given channel no faster than the given
+// RateLimiter allows. This can be used to rate limit the values read from the Out channel of an Unbounded channel. The
+// returned channel is closed once the given channel is closed, or the given context.Context is done.
+func RateLimited[T any](ctx context.Context, in <-chan T, limiter RateLimiter) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+
+				if err := limiter.Wait(ctx); err != nil {
+					return
+				}
+
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
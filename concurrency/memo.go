@@ -0,0 +1,246 @@
+package concurrency
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type flight[V any] struct {
+	wg   sync.WaitGroup
+	val  V
+	err  error
+	dups int
+}
+
+// SingleFlight de-duplicates concurrent calls for the same key so that only one of them is executed at a time. The
+// zero value is ready to use.
+type SingleFlight[K comparable, V any] struct {
+	mutex   sync.Mutex
+	flights map[K]*flight[V]
+}
+
+// Do calls the given function for the given key, unless a call for that key is already in flight. In that case Do
+// waits for the in-flight call to finish and returns its result. shared is true if the result was given to multiple
+// callers. Panics within the function are recovered and returned to every caller as a PanicError.
+func (sf *SingleFlight[K, V]) Do(key K, fun func() (V, error)) (val V, err error, shared bool) {
+	sf.mutex.Lock()
+	if sf.flights == nil {
+		sf.flights = make(map[K]*flight[V])
+	}
+	if f, ok := sf.flights[key]; ok {
+		f.dups++
+		sf.mutex.Unlock()
+		f.wg.Wait()
+		return f.val, f.err, true
+	}
+
+	f := &flight[V]{}
+	f.wg.Add(1)
+	sf.flights[key] = f
+	sf.mutex.Unlock()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				f.err = newPanicError(r)
+			}
+		}()
+		f.val, f.err = fun()
+	}()
+
+	sf.mutex.Lock()
+	if sf.flights[key] == f {
+		delete(sf.flights, key)
+	}
+	shared = f.dups > 0
+	sf.mutex.Unlock()
+	f.wg.Done()
+	return f.val, f.err, shared
+}
+
+// Forget stops de-duplicating calls for the given key. Subsequent calls to Do for the key will execute their function
+// rather than waiting for any call that is currently in flight.
+func (sf *SingleFlight[K, V]) Forget(key K) {
+	sf.mutex.Lock()
+	defer sf.mutex.Unlock()
+	delete(sf.flights, key)
+}
+
+// MemoOptions configures a Memo created using Memoize.
+type MemoOptions struct {
+	// TTL is how long a computed value is cached for. If TTL <= 0 then values never expire.
+	TTL time.Duration
+	// MaxSize is the maximum number of values that are cached. Once this is reached, the least recently used value is
+	// evicted. If MaxSize <= 0 then there is no limit.
+	MaxSize int
+	// Clock is used to expire values. If Clock is nil then SystemClock is used.
+	Clock Clock
+}
+
+// MemoStats are the statistics for a Memo.
+type MemoStats struct {
+	// Hits is the number of calls to Memo.Get that returned a cached value.
+	Hits uint64
+	// Misses is the number of calls to Memo.Get that did not find a cached value.
+	Misses uint64
+	// Shared is the number of misses that waited for a computation of the same key that was already in flight, rather
+	// than computing the value themselves.
+	Shared uint64
+	// Evictions is the number of values that were evicted because MemoOptions.MaxSize was reached.
+	Evictions uint64
+	// Expirations is the number of values that were removed because they were older than MemoOptions.TTL.
+	Expirations uint64
+	// Size is the number of values that are currently cached.
+	Size int
+}
+
+// HitRatio returns the ratio of Hits to the total number of calls to Memo.Get. Returns 0 if there have been no calls.
+func (ms MemoStats) HitRatio() float64 {
+	if total := ms.Hits + ms.Misses; total > 0 {
+		return float64(ms.Hits) / float64(total)
+	}
+	return 0
+}
+
+type memoEntry[K comparable, V any] struct {
+	key     K
+	val     V
+	expires time.Time
+}
+
+// Memo caches the results of an expensive function. Concurrent calls for the same key are de-duplicated using a
+// SingleFlight. Errors returned by the function are not cached.
+type Memo[K comparable, V any] struct {
+	fun    func(key K) (V, error)
+	opts   MemoOptions
+	clock  Clock
+	flight SingleFlight[K, V]
+
+	// mutex guards all the following fields
+	mutex   sync.Mutex
+	entries map[K]*list.Element
+	// order contains memoEntry(s) ordered from most to least recently used
+	order *list.List
+	stats MemoStats
+}
+
+// Memoize creates a Memo which caches the results of the given function as configured by the given MemoOptions.
+func Memoize[K comparable, V any](fun func(key K) (V, error), opts MemoOptions) *Memo[K, V] {
+	return &Memo[K, V]{
+		fun:     fun,
+		opts:    opts,
+		clock:   clockOrSystem(opts.Clock),
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// lookup returns the cached value for the given key, removing it if it has expired. The mutex must be held.
+func (m *Memo[K, V]) lookup(key K) (val V, ok bool) {
+	element, ok := m.entries[key]
+	if !ok {
+		return
+	}
+
+	entry := element.Value.(*memoEntry[K, V])
+	if m.opts.TTL > 0 && !m.clock.Now().Before(entry.expires) {
+		m.remove(element)
+		m.stats.Expirations++
+		return val, false
+	}
+	m.order.MoveToFront(element)
+	return entry.val, true
+}
+
+// remove removes the given element from the cache. The mutex must be held.
+func (m *Memo[K, V]) remove(element *list.Element) {
+	m.order.Remove(element)
+	delete(m.entries, element.Value.(*memoEntry[K, V]).key)
+}
+
+// store caches the given value for the given key, evicting the least recently used value if the cache is full. The
+// mutex must be held.
+func (m *Memo[K, V]) store(key K, val V) {
+	entry := &memoEntry[K, V]{key: key, val: val}
+	if m.opts.TTL > 0 {
+		entry.expires = m.clock.Now().Add(m.opts.TTL)
+	}
+
+	if element, ok := m.entries[key]; ok {
+		element.Value = entry
+		m.order.MoveToFront(element)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(entry)
+	if m.opts.MaxSize > 0 && m.order.Len() > m.opts.MaxSize {
+		m.remove(m.order.Back())
+		m.stats.Evictions++
+	}
+}
+
+// Get returns the cached value for the given key. If there is no cached value, or it has expired, then the value is
+// computed, cached, and returned.
+func (m *Memo[K, V]) Get(key K) (V, error) {
+	m.mutex.Lock()
+	if val, ok := m.lookup(key); ok {
+		m.stats.Hits++
+		m.mutex.Unlock()
+		return val, nil
+	}
+	m.stats.Misses++
+	m.mutex.Unlock()
+
+	computed := false
+	val, err, _ := m.flight.Do(key, func() (V, error) {
+		computed = true
+		val, err := m.fun(key)
+		if err == nil {
+			m.mutex.Lock()
+			m.store(key, val)
+			m.mutex.Unlock()
+		}
+		return val, err
+	})
+
+	if !computed {
+		m.mutex.Lock()
+		m.stats.Shared++
+		m.mutex.Unlock()
+	}
+	return val, err
+}
+
+// Forget removes the cached value for the given key.
+func (m *Memo[K, V]) Forget(key K) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if element, ok := m.entries[key]; ok {
+		m.remove(element)
+	}
+}
+
+// Purge removes all cached values. Statistics are not reset.
+func (m *Memo[K, V]) Purge() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.entries = make(map[K]*list.Element)
+	m.order.Init()
+}
+
+// Len returns the number of cached values, including any that have expired but have not yet been removed.
+func (m *Memo[K, V]) Len() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.order.Len()
+}
+
+// Stats returns the current MemoStats for the Memo.
+func (m *Memo[K, V]) Stats() MemoStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	stats := m.stats
+	stats.Size = m.order.Len()
+	return stats
+}
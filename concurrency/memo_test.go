@@ -0,0 +1,84 @@
+package concurrency
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForDups blocks until n callers are waiting for the in-flight call for the given key to finish.
+func waitForDups[K comparable, V any](t *testing.T, sf *SingleFlight[K, V], key K, n int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		dups := 0
+		sf.mutex.Lock()
+		if f, ok := sf.flights[key]; ok {
+			dups = f.dups
+		}
+		sf.mutex.Unlock()
+
+		if dups >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d callers joined the in-flight call, expected: %d", dups, n)
+		}
+		runtime.Gosched()
+	}
+}
+
+func TestSingleFlight(t *testing.T) {
+	var sf SingleFlight[string, int]
+	var calls int64
+	started, release := make(chan struct{}), make(chan struct{})
+	fun := func() (int, error) {
+		if atomic.AddInt64(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	var sharedCount int64
+	do := func() {
+		defer wg.Done()
+		val, err, shared := sf.Do("key", fun)
+		if val != 42 || err != nil {
+			t.Errorf("Got %d, %v, expected: 42, nil", val, err)
+		}
+		if shared {
+			atomic.AddInt64(&sharedCount, 1)
+		}
+	}
+
+	// The other callers only start once the first call is in flight, and the call is only released once they have all
+	// joined it
+	wg.Add(10)
+	go do()
+	<-started
+	for i := 0; i < 9; i++ {
+		go do()
+	}
+	waitForDups(t, &sf, "key", 9)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Function was called %d times, expected: %d", calls, 1)
+	}
+	if sharedCount != 10 {
+		t.Errorf("%d callers got a shared result, expected: %d", sharedCount, 10)
+	}
+
+	// Panics are returned as errors to every caller
+	_, err, _ := sf.Do("panic", func() (int, error) { panic("oops") })
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Value != "oops" {
+		t.Errorf("Got error: \"%v\", expected a PanicError", err)
+	}
+}
@@ -0,0 +1,392 @@
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PipelineOptions configures a Pipeline created with NewPipeline.
+type PipelineOptions struct {
+	// Unbounded backs the output of each stage with an Unbounded channel so that slow stages never block the stages
+	// upstream of them.
+	Unbounded bool
+	// OnError is called with every error returned by a stage, including recovered panics which are passed as a
+	// PanicError. It can be called from multiple goroutines at once.
+	OnError func(err error)
+}
+
+// Pipeline manages a set of stages that are connected together using channels. Each stage runs within its own
+// goroutine, and stops when the Pipeline's context.Context is cancelled. The first error returned by any stage will
+// cancel the Pipeline, and will be returned by Pipeline.Wait.
+//
+// Stages are created using the Map, Filter, Batch, FanOut, FanIn, Tee, and Merge functions. Values can be fed into a
+// Pipeline using Source.
+type Pipeline struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelFunc
+	opts   PipelineOptions
+	wg     sync.WaitGroup
+
+	// errMutex guards err which is the first error returned by a stage
+	errMutex sync.Mutex
+	err      error
+}
+
+// NewPipeline creates a new Pipeline that is cancelled when the given context.Context is cancelled.
+func NewPipeline(ctx context.Context, opts PipelineOptions) *Pipeline {
+	p := &Pipeline{parent: ctx, opts: opts}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	return p
+}
+
+// Context returns the context.Context that is cancelled when the Pipeline is cancelled.
+func (p *Pipeline) Context() context.Context { return p.ctx }
+
+// Cancel stops every stage in the Pipeline.
+func (p *Pipeline) Cancel() { p.cancel() }
+
+// Err returns the first error returned by a stage, or nil if no stage has returned an error yet.
+func (p *Pipeline) Err() error {
+	p.errMutex.Lock()
+	defer p.errMutex.Unlock()
+	return p.err
+}
+
+// Wait blocks until every stage in the Pipeline has exited, then returns the first error returned by a stage. If no
+// stage returned an error but the context.Context given to NewPipeline was cancelled, its error is returned instead.
+//
+// Wait should be called after the output of the last stage has been read in full, as the Pipeline's context.Context is
+// cancelled once all stages have exited.
+func (p *Pipeline) Wait() error {
+	p.wg.Wait()
+	p.cancel()
+	if err := p.Err(); err != nil {
+		return err
+	}
+	return p.parent.Err()
+}
+
+// fail records the given error, passes it to PipelineOptions.OnError, then cancels the Pipeline.
+func (p *Pipeline) fail(err error) {
+	p.errMutex.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.errMutex.Unlock()
+
+	if p.opts.OnError != nil {
+		p.opts.OnError(err)
+	}
+	p.cancel()
+}
+
+// stage runs the given function within its own goroutine. Errors returned by the function, and panics, will fail the
+// Pipeline.
+func (p *Pipeline) stage(fun func() error) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				p.fail(newPanicError(r))
+			}
+		}()
+
+		if err := fun(); err != nil {
+			p.fail(err)
+		}
+	}()
+}
+
+// stageChan creates the channels that a stage will write its output to. These are either the channels of an Unbounded
+// channel, or an unbuffered channel.
+func stageChan[T any](p *Pipeline) (chan<- T, <-chan T) {
+	if p.opts.Unbounded {
+		u := NewUnboundedContext(p.ctx, UnboundedOptions[T]{})
+		return u.In(), u.Out()
+	}
+	ch := make(chan T)
+	return ch, ch
+}
+
+// send writes the given value to the given channel. Returns false if the Pipeline was cancelled before the value could
+// be written.
+func send[T any](p *Pipeline, ch chan<- T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-p.ctx.Done():
+		return false
+	}
+}
+
+// recv reads a value from the given channel. Returns false if the channel was closed, or if the Pipeline was cancelled.
+func recv[T any](p *Pipeline, ch <-chan T) (v T, ok bool) {
+	select {
+	case v, ok = <-ch:
+		return
+	case <-p.ctx.Done():
+		return v, false
+	}
+}
+
+// Source creates a stage which writes each of the given values, in order, to the returned channel.
+func Source[T any](p *Pipeline, values ...T) <-chan T {
+	in, out := stageChan[T](p)
+	p.stage(func() error {
+		defer close(in)
+		for _, v := range values {
+			if !send(p, in, v) {
+				break
+			}
+		}
+		return nil
+	})
+	return out
+}
+
+// Map creates a stage which calls the given function on each value read from the given channel, and writes the result
+// to the returned channel.
+func Map[In any, Out any](p *Pipeline, in <-chan In, fun func(ctx context.Context, value In) (Out, error)) <-chan Out {
+	outIn, out := stageChan[Out](p)
+	p.stage(func() error {
+		defer close(outIn)
+		for {
+			v, ok := recv(p, in)
+			if !ok {
+				return nil
+			}
+
+			o, err := fun(p.ctx, v)
+			if err != nil {
+				return err
+			}
+
+			if !send(p, outIn, o) {
+				return nil
+			}
+		}
+	})
+	return out
+}
+
+// Filter creates a stage which writes the values read from the given channel, for which the given predicate returns
+// true, to the returned channel.
+func Filter[T any](p *Pipeline, in <-chan T, fun func(ctx context.Context, value T) (bool, error)) <-chan T {
+	outIn, out := stageChan[T](p)
+	p.stage(func() error {
+		defer close(outIn)
+		for {
+			v, ok := recv(p, in)
+			if !ok {
+				return nil
+			}
+
+			keep, err := fun(p.ctx, v)
+			if err != nil {
+				return err
+			}
+
+			if keep && !send(p, outIn, v) {
+				return nil
+			}
+		}
+	})
+	return out
+}
+
+// Batch creates a stage which groups the values read from the given channel into batches of the given size. A batch is
+// written to the returned channel once it is full, or once the given timeout has elapsed since the first value was
+// added to the batch. If timeout <= 0 then batches are only written when they are full. Any partial batch is written
+// once the given channel is closed. Batch panics if size is less than 1.
+func Batch[T any](p *Pipeline, in <-chan T, size int, timeout time.Duration) <-chan []T {
+	if size < 1 {
+		panic(fmt.Errorf("batch size must be at least 1, not %d", size))
+	}
+	outIn, out := stageChan[[]T](p)
+	p.stage(func() error {
+		defer close(outIn)
+		batch := make([]T, 0, size)
+		var timer *time.Timer
+		var timeoutCh <-chan time.Time
+
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeoutCh = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			full := batch
+			batch = make([]T, 0, size)
+			return send(p, outIn, full)
+		}
+
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return nil
+				}
+
+				batch = append(batch, v)
+				if len(batch) >= size {
+					if !flush() {
+						return nil
+					}
+				} else if len(batch) == 1 && timeout > 0 {
+					timer = time.NewTimer(timeout)
+					timeoutCh = timer.C
+				}
+			case <-timeoutCh:
+				timer, timeoutCh = nil, nil
+				if !flush() {
+					return nil
+				}
+			case <-p.ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return nil
+			}
+		}
+	})
+	return out
+}
+
+// FanOut creates n stages which each read values from the given channel and write them to their own output channel.
+// Each value is written to only one of the returned channels: whichever stage was ready to read it first. FanOut panics
+// if n is less than 1.
+func FanOut[T any](p *Pipeline, in <-chan T, n int) []<-chan T {
+	if n < 1 {
+		panic(fmt.Errorf("cannot fan out to %d channels", n))
+	}
+	outs := make([]<-chan T, n)
+	for i := 0; i < n; i++ {
+		var outIn chan<- T
+		outIn, outs[i] = stageChan[T](p)
+		p.stage(func() error {
+			defer close(outIn)
+			for {
+				v, ok := recv(p, in)
+				if !ok || !send(p, outIn, v) {
+					return nil
+				}
+			}
+		})
+	}
+	return outs
+}
+
+// FanIn creates a stage which writes the values read from all the given channels to the returned channel. The
+// returned channel is closed once every given channel has been closed.
+func FanIn[T any](p *Pipeline, ins ...<-chan T) <-chan T {
+	outIn, out := stageChan[T](p)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		in := in
+		p.stage(func() error {
+			defer wg.Done()
+			for {
+				v, ok := recv(p, in)
+				if !ok || !send(p, outIn, v) {
+					return nil
+				}
+			}
+		})
+	}
+
+	p.stage(func() error {
+		wg.Wait()
+		close(outIn)
+		return nil
+	})
+	return out
+}
+
+// Tee creates a stage which writes each value read from the given channel to all n of the returned channels. A value is
+// written to every channel before the next value is read, so each channel should be read concurrently, or the
+// Pipeline should use PipelineOptions.Unbounded. Tee panics if n is less than 1.
+func Tee[T any](p *Pipeline, in <-chan T, n int) []<-chan T {
+	if n < 1 {
+		panic(fmt.Errorf("cannot tee to %d channels", n))
+	}
+	outIns := make([]chan<- T, n)
+	outs := make([]<-chan T, n)
+	for i := 0; i < n; i++ {
+		outIns[i], outs[i] = stageChan[T](p)
+	}
+
+	p.stage(func() error {
+		defer func() {
+			for _, outIn := range outIns {
+				close(outIn)
+			}
+		}()
+
+		for {
+			v, ok := recv(p, in)
+			if !ok {
+				return nil
+			}
+
+			for _, outIn := range outIns {
+				if !send(p, outIn, v) {
+					return nil
+				}
+			}
+		}
+	})
+	return outs
+}
+
+// Merge creates a stage which merges the values read from the given channels, each of which should produce values in
+// the order defined by less, into the returned channel so that it also produces values in that order. less should
+// return true when a should come before b.
+func Merge[T any](p *Pipeline, less func(a, b T) bool, ins ...<-chan T) <-chan T {
+	outIn, out := stageChan[T](p)
+	p.stage(func() error {
+		defer close(outIn)
+		// Read the first value from each of the channels. Channels that are closed are removed.
+		heads := make([]T, 0, len(ins))
+		open := make([]<-chan T, 0, len(ins))
+		for _, in := range ins {
+			if v, ok := recv(p, in); ok {
+				heads = append(heads, v)
+				open = append(open, in)
+			}
+		}
+
+		for len(open) > 0 {
+			min := 0
+			for i := 1; i < len(heads); i++ {
+				if less(heads[i], heads[min]) {
+					min = i
+				}
+			}
+
+			if !send(p, outIn, heads[min]) {
+				return nil
+			}
+
+			// Replace the head of the channel that we just wrote from
+			if v, ok := recv(p, open[min]); ok {
+				heads[min] = v
+			} else {
+				if p.ctx.Err() != nil {
+					return nil
+				}
+				heads = append(heads[:min], heads[min+1:]...)
+				open = append(open[:min], open[min+1:]...)
+			}
+		}
+		return nil
+	})
+	return out
+}
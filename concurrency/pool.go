@@ -0,0 +1,298 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is the error that is returned in place of a panic that was recovered from within a task.
+type PanicError struct {
+	// Value is the value that was passed to panic.
+	Value any
+	// Stack is the stack trace of the goroutine that panicked, as returned by debug.Stack.
+	Stack []byte
+}
+
+func newPanicError(value any) *PanicError {
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v\n\n%s", pe.Value, pe.Stack)
+}
+
+// Unwrap returns the value that was passed to panic if it is an error, otherwise nil.
+func (pe *PanicError) Unwrap() error {
+	if err, ok := pe.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Future is the eventual result of a task submitted to a Pool.
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+func (f *Future[T]) resolve(value T, err error) {
+	f.value, f.err = value, err
+	close(f.done)
+}
+
+// Done returns a channel that is closed once the result of the Future is available.
+func (f *Future[T]) Done() <-chan struct{} { return f.done }
+
+// Wait blocks until the result of the Future is available, then returns it.
+func (f *Future[T]) Wait() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// WaitContext blocks until either the result of the Future is available, or the given context.Context is done. In the
+// latter case the zero value and the context's error are returned.
+func (f *Future[T]) WaitContext(ctx context.Context) (value T, err error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		return value, ctx.Err()
+	}
+}
+
+// Result is a result streamed from Pool.Results.
+type Result[T any] struct {
+	// Index is the order in which the task that produced this Result was submitted, starting at 0.
+	Index int
+	Value T
+	Err   error
+}
+
+// ResultOrder is the order in which Result(s) are streamed from Pool.Results.
+type ResultOrder int
+
+const (
+	// ResultsNone does not stream any Result(s). The results of tasks are only available through their Future.
+	ResultsNone ResultOrder = iota
+	// ResultsUnordered streams Result(s) as soon as their tasks have finished.
+	ResultsUnordered
+	// ResultsOrdered streams Result(s) in the order that their tasks were submitted.
+	ResultsOrdered
+)
+
+// ErrPoolClosed is returned by Pool.Submit once Pool.Shutdown has been called.
+var ErrPoolClosed = errors.New("pool has been shut down")
+
+// PoolOptions configures a Pool created with NewPool.
+type PoolOptions struct {
+	// Workers is the number of goroutines that execute tasks. If Workers <= 0 then runtime.GOMAXPROCS(0) is used.
+	Workers int
+	// QueueSize is the number of tasks that can be submitted before Pool.Submit blocks. This is ignored when Unbounded
+	// is set.
+	QueueSize int
+	// Unbounded backs the job queue with an Unbounded channel so that Pool.Submit never blocks.
+	Unbounded bool
+	// Results is the order in which Result(s) are streamed from Pool.Results.
+	Results ResultOrder
+}
+
+type poolJob[In, Out any] struct {
+	index  int
+	in     In
+	future *Future[Out]
+}
+
+// Pool is a bounded pool of worker goroutines that each execute the same function on the submitted inputs.
+type Pool[In, Out any] struct {
+	fn     func(ctx context.Context, in In) (Out, error)
+	opts   PoolOptions
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	jobsIn  chan<- poolJob[In, Out]
+	jobsOut <-chan poolJob[In, Out]
+
+	results chan<- Result[Out]
+	out     <-chan Result[Out]
+
+	// mutex guards closed, and sending is incremented under it so that the job queue is only closed once every
+	// in-flight Submit has returned
+	mutex   sync.Mutex
+	closed  bool
+	closing chan struct{}
+	sending sync.WaitGroup
+	// submitMutex is held whilst queueing a job so that indices are queued in order. submitted is guarded by it.
+	submitMutex sync.Mutex
+	submitted   int
+	workers     sync.WaitGroup
+	done        chan struct{}
+}
+
+// NewPool creates a Pool configured by the given PoolOptions, and starts its workers. Each worker calls fn on the
+// inputs that are submitted using Pool.Submit. The context.Context passed to fn is cancelled when Pool.Shutdown gives
+// up waiting for tasks to finish.
+func NewPool[In, Out any](opts PoolOptions, fn func(ctx context.Context, in In) (Out, error)) *Pool[In, Out] {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+
+	p := &Pool[In, Out]{
+		fn:      fn,
+		opts:    opts,
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	if opts.Unbounded {
+		p.jobsIn, p.jobsOut = InOut[poolJob[In, Out]]()
+	} else {
+		jobs := make(chan poolJob[In, Out], opts.QueueSize)
+		p.jobsIn, p.jobsOut = jobs, jobs
+	}
+
+	switch opts.Results {
+	case ResultsUnordered:
+		p.results, p.out = InOut[Result[Out]]()
+	case ResultsOrdered:
+		var unordered <-chan Result[Out]
+		p.results, unordered = InOut[Result[Out]]()
+		p.out = reorderResults(unordered)
+	}
+
+	p.workers.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go p.worker()
+	}
+
+	go func() {
+		p.workers.Wait()
+		if p.results != nil {
+			close(p.results)
+		}
+		p.cancel()
+		close(p.done)
+	}()
+	return p
+}
+
+// reorderResults reads the Result(s) from the given channel and writes them to the returned channel in Index order.
+func reorderResults[T any](unordered <-chan Result[T]) <-chan Result[T] {
+	in, out := InOut[Result[T]]()
+	go func() {
+		next := 0
+		pending := make(map[int]Result[T])
+		for result := range unordered {
+			pending[result.Index] = result
+			for {
+				r, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				in <- r
+				next++
+			}
+		}
+		close(in)
+	}()
+	return out
+}
+
+func (p *Pool[In, Out]) worker() {
+	defer p.workers.Done()
+	for job := range p.jobsOut {
+		value, err := p.run(job.in)
+		job.future.resolve(value, err)
+		if p.results != nil {
+			p.results <- Result[Out]{Index: job.index, Value: value, Err: err}
+		}
+	}
+}
+
+// run calls the Pool's function on the given input, converting any panics into a PanicError.
+func (p *Pool[In, Out]) run(in In) (value Out, err error) {
+	// Don't bother running tasks once the Pool has been cancelled
+	if err = p.ctx.Err(); err != nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+	return p.fn(p.ctx, in)
+}
+
+// Submit queues the given input to be processed by a worker, and returns a Future for its result. Submit will block if
+// the job queue is full, unless PoolOptions.Unbounded is set. ErrPoolClosed is returned if the Pool has been shut down,
+// including whilst Submit is blocked.
+func (p *Pool[In, Out]) Submit(in In) (*Future[Out], error) {
+	p.submitMutex.Lock()
+	defer p.submitMutex.Unlock()
+
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return nil, ErrPoolClosed
+	}
+	p.sending.Add(1)
+	p.mutex.Unlock()
+	defer p.sending.Done()
+
+	// The index is only used up once the job has been queued, so that ordered results never wait on an abandoned job
+	job := poolJob[In, Out]{index: p.submitted, in: in, future: newFuture[Out]()}
+	select {
+	case p.jobsIn <- job:
+		p.submitted++
+		return job.future, nil
+	case <-p.closing:
+		return nil, ErrPoolClosed
+	}
+}
+
+// Results returns the channel on which Result(s) are streamed in the order set by PoolOptions.Results. The channel is
+// closed once the Pool has been shut down and all tasks have finished. If PoolOptions.Results is ResultsNone then nil
+// is returned.
+//
+// The channel is backed by an Unbounded channel so workers never block on readers.
+func (p *Pool[In, Out]) Results() <-chan Result[Out] { return p.out }
+
+// Done returns a channel that is closed once all the workers have exited after Pool.Shutdown.
+func (p *Pool[In, Out]) Done() <-chan struct{} { return p.done }
+
+// Shutdown stops the Pool from accepting new tasks, then waits for all the queued tasks to finish. If the given
+// context.Context is done before then, the context.Context passed to each task is cancelled, any remaining queued tasks
+// are resolved with context.Canceled, and the context's error is returned.
+//
+// Any calls to Submit that are blocked on a full job queue return ErrPoolClosed.
+func (p *Pool[In, Out]) Shutdown(ctx context.Context) error {
+	p.mutex.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.closing)
+		go func() {
+			p.sending.Wait()
+			close(p.jobsIn)
+		}()
+	}
+	p.mutex.Unlock()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,38 @@
+package concurrency
+
+import "context"
+
+// PriorityItem is a value with a priority that can be written to a channel created by PriorityItemInOut.
+type PriorityItem[T any] struct {
+	Value    T
+	Priority int
+}
+
+// PriorityItemLess is a less function for PriorityItem(s) which orders items with higher priorities first.
+func PriorityItemLess[T any](a, b PriorityItem[T]) bool { return a.Priority > b.Priority }
+
+// NewPriorityUnbounded creates a new Unbounded channel whose Out channel always yields the highest priority value that
+// is currently buffered, rather than the oldest. less should return true when a has a higher priority than b. Values
+// with equal priorities are read in the order that they were written.
+//
+// The given context.Context and UnboundedOptions work in the same way as they do for NewUnboundedContext, except that
+// the OverflowDropOldest policy discards the lowest priority buffered value, rather than the oldest. Unbounded.Drain
+// will return unread values in priority order.
+func NewPriorityUnbounded[T any](ctx context.Context, less func(a, b T) bool, opts UnboundedOptions[T]) *Unbounded[T] {
+	evictable := opts.SoftCap > 0 && opts.Overflow == OverflowDropOldest
+	return newUnbounded[T](ctx, opts, newHeapQueue(less, evictable))
+}
+
+// PriorityInOut creates infinite channels that don't block when written to, and where the highest priority buffered
+// value is always read first. less should return true when a has a higher priority than b. It is shorthand for
+// creating an Unbounded channel with NewPriorityUnbounded and returning its In and Out channels.
+func PriorityInOut[T any](less func(a, b T) bool) (chan<- T, <-chan T) {
+	u := NewPriorityUnbounded(context.Background(), less, UnboundedOptions[T]{})
+	return u.In(), u.Out()
+}
+
+// PriorityItemInOut creates infinite channels of PriorityItem(s) where the buffered item with the highest Priority is
+// always read first.
+func PriorityItemInOut[T any]() (chan<- PriorityItem[T], <-chan PriorityItem[T]) {
+	return PriorityInOut(PriorityItemLess[T])
+}
@@ -0,0 +1,109 @@
+package concurrency
+
+import "github.com/andygello555/gotils/v2/structs"
+
+// queue is the internal queue used by Unbounded to buffer values.
+type queue[T any] interface {
+	len() int
+	push(v T)
+	// peek returns the value at the head of the queue, or the zero value if the queue is empty.
+	peek() T
+	// pop removes and returns the value at the head of the queue, or the zero value if the queue is empty.
+	pop() T
+	// evict removes and returns the value that should be discarded to make room for a new value under the
+	// OverflowDropOldest policy, or the zero value if the queue is empty.
+	evict() T
+}
+
+// dequeQueue is a FIFO queue that is backed by a structs.Deque. The Deque's ring buffer doubles in size when it is
+// full and halves in size when it is a quarter full, so that the memory held by the queue is released once a backlog
+// has cleared.
+type dequeQueue[T any] struct {
+	deque *structs.Deque[T]
+}
+
+func newDequeQueue[T any](minCapacity int) *dequeQueue[T] {
+	return &dequeQueue[T]{deque: structs.NewDeque[T](minCapacity)}
+}
+
+func (q *dequeQueue[T]) len() int { return q.deque.Len() }
+
+func (q *dequeQueue[T]) push(v T) { q.deque.PushBack(v) }
+
+func (q *dequeQueue[T]) peek() (v T) {
+	v, _ = q.deque.Front()
+	return
+}
+
+func (q *dequeQueue[T]) pop() (v T) {
+	v, _ = q.deque.PopFront()
+	return
+}
+
+// evict removes the oldest value, which is the value at the head of the queue.
+func (q *dequeQueue[T]) evict() T { return q.pop() }
+
+// heapEntry is a value within a heapQueue, along with its handles within the heapQueue's PriorityQueue(s).
+type heapEntry[T any] struct {
+	value       T
+	best, worst *structs.PriorityQueueItem[*heapEntry[T]]
+}
+
+// heapQueue is a priority queue that pops the value that is least according to the less function first. Values with
+// equal priority are popped in FIFO order.
+//
+// If the heapQueue is evictable then a second PriorityQueue is kept that is ordered by lowest priority first, so that
+// evict can remove the lowest priority value in O(log n).
+type heapQueue[T any] struct {
+	best, worst *structs.PriorityQueue[*heapEntry[T]]
+}
+
+func newHeapQueue[T any](less func(a, b T) bool, evictable bool) *heapQueue[T] {
+	q := &heapQueue[T]{best: structs.NewPriorityQueue(func(a, b *heapEntry[T]) bool { return less(a.value, b.value) })}
+	if evictable {
+		q.worst = structs.NewPriorityQueue(func(a, b *heapEntry[T]) bool { return less(b.value, a.value) })
+	}
+	return q
+}
+
+func (q *heapQueue[T]) len() int { return q.best.Len() }
+
+func (q *heapQueue[T]) push(v T) {
+	entry := &heapEntry[T]{value: v}
+	entry.best = q.best.Push(entry)
+	if q.worst != nil {
+		entry.worst = q.worst.Push(entry)
+	}
+}
+
+func (q *heapQueue[T]) peek() (v T) {
+	if entry, ok := q.best.Peek(); ok {
+		v = entry.value
+	}
+	return
+}
+
+func (q *heapQueue[T]) pop() (v T) {
+	entry, ok := q.best.Pop()
+	if !ok {
+		return
+	}
+	if q.worst != nil {
+		q.worst.Remove(entry.worst)
+	}
+	return entry.value
+}
+
+// evict removes the lowest priority value. Of the values with the lowest priority, the oldest is removed. If the
+// heapQueue is not evictable then the highest priority value is removed instead.
+func (q *heapQueue[T]) evict() (v T) {
+	if q.worst == nil {
+		return q.pop()
+	}
+	entry, ok := q.worst.Pop()
+	if !ok {
+		return
+	}
+	q.best.Remove(entry.best)
+	return entry.value
+}
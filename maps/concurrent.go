@@ -0,0 +1,242 @@
+package maps
+
+import (
+	"github.com/andygello555/gotils/v2/misc"
+	"hash/maphash"
+	"sync"
+)
+
+const defaultShards = 32
+
+type shard[K comparable, V any] struct {
+	sync.RWMutex
+	m map[K]V
+}
+
+// ConcurrentMap is a map that is safe for concurrent use by multiple goroutines. Keys are spread across a number of
+// shards, each with their own lock, to reduce lock contention.
+//
+// Operations that span the entire map, such as ConcurrentMap.Snapshot, ConcurrentMap.Filter, ConcurrentMap.Union, and
+// ConcurrentMap.Difference, lock every shard so that they see and produce a consistent view of the map.
+type ConcurrentMap[K comparable, V any] struct {
+	hash   func(key K) uint64
+	shards []*shard[K, V]
+}
+
+// NewConcurrentMap creates a new, empty, ConcurrentMap with the given number of shards. If shards <= 0 then a default
+// of 32 shards is used. Keys are assigned to shards using misc.Hash, which hashes strings and numbers directly but
+// falls back to reflection for any other types. Use NewConcurrentMapFunc to avoid this for other key types.
+func NewConcurrentMap[K comparable, V any](shards int) *ConcurrentMap[K, V] {
+	seed := maphash.MakeSeed()
+	return NewConcurrentMapFunc[K, V](shards, func(key K) uint64 { return misc.Hash(seed, key) })
+}
+
+// NewConcurrentMapFunc creates a new, empty, ConcurrentMap with the given number of shards that assigns keys to shards
+// using the given hash function. Keys that are equal must produce the same hash. If shards <= 0 then a default of 32
+// shards is used.
+func NewConcurrentMapFunc[K comparable, V any](shards int, hash func(key K) uint64) *ConcurrentMap[K, V] {
+	if shards <= 0 {
+		shards = defaultShards
+	}
+
+	cm := &ConcurrentMap[K, V]{
+		hash:   hash,
+		shards: make([]*shard[K, V], shards),
+	}
+	for i := range cm.shards {
+		cm.shards[i] = &shard[K, V]{m: make(map[K]V)}
+	}
+	return cm
+}
+
+// NewConcurrentMapFrom creates a new ConcurrentMap with the given number of shards that contains the key-value pairs
+// of the given map.
+func NewConcurrentMapFrom[K comparable, V any](m map[K]V, shards int) *ConcurrentMap[K, V] {
+	cm := NewConcurrentMap[K, V](shards)
+	cm.Union(m)
+	return cm
+}
+
+func (cm *ConcurrentMap[K, V]) shard(key K) *shard[K, V] {
+	return cm.shards[cm.hash(key)%uint64(len(cm.shards))]
+}
+
+// lockAll locks every shard in order, so that lockAll can never deadlock with itself.
+func (cm *ConcurrentMap[K, V]) lockAll(write bool) {
+	for _, s := range cm.shards {
+		if write {
+			s.Lock()
+		} else {
+			s.RLock()
+		}
+	}
+}
+
+func (cm *ConcurrentMap[K, V]) unlockAll(write bool) {
+	for _, s := range cm.shards {
+		if write {
+			s.Unlock()
+		} else {
+			s.RUnlock()
+		}
+	}
+}
+
+// Load returns the value stored for the given key, and whether the key exists.
+func (cm *ConcurrentMap[K, V]) Load(key K) (val V, ok bool) {
+	s := cm.shard(key)
+	s.RLock()
+	defer s.RUnlock()
+	val, ok = s.m[key]
+	return
+}
+
+// Store sets the value for the given key.
+func (cm *ConcurrentMap[K, V]) Store(key K, val V) {
+	s := cm.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	s.m[key] = val
+}
+
+// LoadOrStore returns the existing value for the given key if it exists. Otherwise, it stores and returns the given
+// value. loaded is true if the value was loaded, and false if it was stored.
+func (cm *ConcurrentMap[K, V]) LoadOrStore(key K, val V) (actual V, loaded bool) {
+	s := cm.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	if actual, loaded = s.m[key]; loaded {
+		return
+	}
+	s.m[key] = val
+	return val, false
+}
+
+// LoadAndDelete deletes the value for the given key, returning the previous value if there was one.
+func (cm *ConcurrentMap[K, V]) LoadAndDelete(key K) (val V, loaded bool) {
+	s := cm.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	if val, loaded = s.m[key]; loaded {
+		delete(s.m, key)
+	}
+	return
+}
+
+// Delete deletes the value for the given key.
+func (cm *ConcurrentMap[K, V]) Delete(key K) {
+	s := cm.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	delete(s.m, key)
+}
+
+// Compute atomically computes a new value for the given key. The given function is passed the current value of the key
+// and whether it exists, and should return the new value and whether to keep it. If keep is false then the key is
+// deleted. Compute returns the new value and whether it was kept.
+//
+// The shard containing the key is locked whilst the function is called, so the function must not access the
+// ConcurrentMap.
+func (cm *ConcurrentMap[K, V]) Compute(key K, fun func(val V, loaded bool) (newVal V, keep bool)) (V, bool) {
+	s := cm.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	val, loaded := s.m[key]
+	newVal, keep := fun(val, loaded)
+	if keep {
+		s.m[key] = newVal
+	} else {
+		delete(s.m, key)
+	}
+	return newVal, keep
+}
+
+// Len returns the number of key-value pairs in the ConcurrentMap.
+func (cm *ConcurrentMap[K, V]) Len() int {
+	cm.lockAll(false)
+	defer cm.unlockAll(false)
+	n := 0
+	for _, s := range cm.shards {
+		n += len(s.m)
+	}
+	return n
+}
+
+// Snapshot returns a plain map containing a consistent copy of the key-value pairs in the ConcurrentMap.
+func (cm *ConcurrentMap[K, V]) Snapshot() map[K]V {
+	cm.lockAll(false)
+	defer cm.unlockAll(false)
+	n := 0
+	for _, s := range cm.shards {
+		n += len(s.m)
+	}
+
+	m := make(map[K]V, n)
+	for _, s := range cm.shards {
+		Union(m, s.m)
+	}
+	return m
+}
+
+// Keys returns the keys within the ConcurrentMap.
+func (cm *ConcurrentMap[K, V]) Keys() []K { return Keys(cm.Snapshot()) }
+
+// Values returns the values within the ConcurrentMap.
+func (cm *ConcurrentMap[K, V]) Values() []V { return Values(cm.Snapshot()) }
+
+// Range calls the given MapRangeFunc on each index-key-value triple within a Snapshot of the ConcurrentMap. Triples are
+// unordered. As the function is called on a Snapshot, it is free to modify the ConcurrentMap.
+func (cm *ConcurrentMap[K, V]) Range(fun MapRangeFunc[K, V]) {
+	RangeKeys(cm.Snapshot(), fun)
+}
+
+// Filter runs the given predicate function on each index-key-value triple. If the predicate returns false for an
+// element, then that element will be removed from the ConcurrentMap. Every shard is locked whilst filtering, so the
+// predicate must not access the ConcurrentMap.
+func (cm *ConcurrentMap[K, V]) Filter(fun func(i int, key K, val V) bool) {
+	cm.lockAll(true)
+	defer cm.unlockAll(true)
+	i := 0
+	for _, s := range cm.shards {
+		Filter(s.m, func(_ int, key K, val V) bool {
+			keep := fun(i, key, val)
+			i++
+			return keep
+		})
+	}
+}
+
+// group splits the given map into one map per shard of the ConcurrentMap.
+func (cm *ConcurrentMap[K, V]) group(m map[K]V) map[*shard[K, V]]map[K]V {
+	groups := make(map[*shard[K, V]]map[K]V)
+	for key, val := range m {
+		s := cm.shard(key)
+		if _, ok := groups[s]; !ok {
+			groups[s] = make(map[K]V)
+		}
+		groups[s][key] = val
+	}
+	return groups
+}
+
+// Union merges the given map into the ConcurrentMap, overriding any matching keys. Every shard is locked whilst
+// merging so the merge appears atomic to other goroutines.
+func (cm *ConcurrentMap[K, V]) Union(src map[K]V) {
+	groups := cm.group(src)
+	cm.lockAll(true)
+	defer cm.unlockAll(true)
+	for s, m := range groups {
+		Union(s.m, m)
+	}
+}
+
+// Difference removes every key-value pair in the ConcurrentMap that also exists in n. Every shard is locked whilst
+// removing so the removal appears atomic to other goroutines.
+func (cm *ConcurrentMap[K, V]) Difference(n map[K]V) {
+	groups := cm.group(n)
+	cm.lockAll(true)
+	defer cm.unlockAll(true)
+	for s, m := range groups {
+		Difference(s.m, m)
+	}
+}
@@ -0,0 +1,179 @@
+package maps
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Cloner can be implemented by types that need to control how they are copied by DeepCopy. Clone should return a deep
+// copy of the receiver that has the same type as the receiver.
+type Cloner interface {
+	Clone() any
+}
+
+var clonerType = reflect.TypeOf((*Cloner)(nil)).Elem()
+
+// UnexportedFieldPolicy decides what DeepCopy does with the unexported fields of structs, which cannot be deep copied
+// using reflection.
+type UnexportedFieldPolicy int
+
+const (
+	// UnexportedShallow copies unexported fields as is, so any maps, slices, or pointers within them are shared with
+	// the original.
+	UnexportedShallow UnexportedFieldPolicy = iota
+	// UnexportedZero leaves unexported fields set to their zero value.
+	UnexportedZero
+)
+
+func (ufp UnexportedFieldPolicy) String() string {
+	switch ufp {
+	case UnexportedShallow:
+		return "Shallow"
+	case UnexportedZero:
+		return "Zero"
+	default:
+		return "Unknown"
+	}
+}
+
+// DeepCopyOptions configures DeepCopyWithOptions.
+type DeepCopyOptions struct {
+	// Unexported is the UnexportedFieldPolicy for the unexported fields of structs. Defaults to UnexportedShallow.
+	Unexported UnexportedFieldPolicy
+}
+
+// deepCopyVisit identifies a map, slice, or pointer that has already been copied.
+type deepCopyVisit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+type deepCopier struct {
+	opts    DeepCopyOptions
+	visited map[deepCopyVisit]reflect.Value
+}
+
+// DeepCopy returns a deep copy of the given value using the default DeepCopyOptions. See DeepCopyWithOptions.
+func DeepCopy[T any](v T) T { return DeepCopyWithOptions(v, DeepCopyOptions{}) }
+
+// DeepCopyWithOptions returns a deep copy of the given value using reflection.
+//
+// Slices, arrays, maps, pointers, interfaces, and the exported fields of structs are copied recursively. Map keys,
+// channels, and functions are not copied. What happens to the unexported fields of structs is decided by the
+// UnexportedFieldPolicy within the given DeepCopyOptions. Bear in mind that some types from the standard library, such
+// as time.Time, rely on their unexported fields.
+//
+// Maps, slices, and pointers that are referenced more than once are only copied once, so the copy has the same shape as
+// the original, and cyclic values can be copied without recursing forever. If a value's type declares a Clone method
+// that implements Cloner, then its Clone method is used instead. A pointer to such a type is copied by cloning the value
+// that it points to. DeepCopyWithOptions panics if a Clone method returns a value of a different type.
+func DeepCopyWithOptions[T any](v T, opts DeepCopyOptions) (cp T) {
+	c := &deepCopier{opts: opts, visited: make(map[deepCopyVisit]reflect.Value)}
+	reflect.ValueOf(&cp).Elem().Set(c.copy(reflect.ValueOf(&v).Elem()))
+	return
+}
+
+// copy returns a deep copy of the given reflect.Value with the same type.
+func (c *deepCopier) copy(src reflect.Value) reflect.Value {
+	if c.isNil(src) {
+		return src
+	}
+
+	if declaresClone(src.Type()) {
+		clone := reflect.ValueOf(src.Interface().(Cloner).Clone())
+		dst := reflect.New(src.Type()).Elem()
+		if clone.IsValid() {
+			if !clone.Type().AssignableTo(src.Type()) {
+				panic(fmt.Errorf("the Clone method of %s returned a %s", src.Type(), clone.Type()))
+			}
+			dst.Set(clone)
+		}
+		return dst
+	}
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		visit := deepCopyVisit{ptr: src.Pointer(), typ: src.Type()}
+		if dst, ok := c.visited[visit]; ok {
+			return dst
+		}
+		dst := reflect.New(src.Type().Elem())
+		c.visited[visit] = dst
+		dst.Elem().Set(c.copy(src.Elem()))
+		return dst
+	case reflect.Interface:
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(c.copy(src.Elem()))
+		return dst
+	case reflect.Map:
+		visit := deepCopyVisit{ptr: src.Pointer(), typ: src.Type()}
+		if dst, ok := c.visited[visit]; ok {
+			return dst
+		}
+		dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.visited[visit] = dst
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+		return dst
+	case reflect.Slice:
+		visit := deepCopyVisit{ptr: src.Pointer(), typ: src.Type(), len: src.Len()}
+		if dst, ok := c.visited[visit]; ok {
+			return dst
+		}
+		dst := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		c.visited[visit] = dst
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(c.copy(src.Index(i)))
+		}
+		return dst
+	case reflect.Array:
+		dst := reflect.New(src.Type()).Elem()
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(c.copy(src.Index(i)))
+		}
+		return dst
+	case reflect.Struct:
+		dst := reflect.New(src.Type()).Elem()
+		if c.opts.Unexported == UnexportedShallow {
+			dst.Set(src)
+		}
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).PkgPath == "" {
+				dst.Field(i).Set(c.copy(src.Field(i)))
+			}
+		}
+		return dst
+	default:
+		return src
+	}
+}
+
+// declaresClone returns whether the given type's Clone method should be used to copy it. A pointer to a type with a
+// value receiver Clone method also implements Cloner, but its Clone method returns the pointed to type, so the pointer
+// is copied as usual and the pointed to value is cloned instead. Interfaces are also copied as usual so that their
+// dynamic value is checked instead.
+func declaresClone(t reflect.Type) bool {
+	switch {
+	case t.Kind() == reflect.Interface:
+		return false
+	case t.Kind() == reflect.Ptr && t.Elem().Implements(clonerType):
+		return false
+	default:
+		return t.Implements(clonerType)
+	}
+}
+
+// isNil returns whether the given reflect.Value is invalid, or is a nil map, slice, pointer, or interface.
+func (c *deepCopier) isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
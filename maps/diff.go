@@ -0,0 +1,476 @@
+package maps
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeType is the type of a Change found by Diff.
+type ChangeType int
+
+const (
+	// ChangeAdded means that the value only exists in the new value.
+	ChangeAdded ChangeType = iota
+	// ChangeRemoved means that the value only exists in the old value.
+	ChangeRemoved
+	// ChangeModified means that the value exists in both, but is different.
+	ChangeModified
+)
+
+func (ct ChangeType) String() string {
+	switch ct {
+	case ChangeAdded:
+		return "Added"
+	case ChangeRemoved:
+		return "Removed"
+	case ChangeModified:
+		return "Modified"
+	default:
+		return "Unknown"
+	}
+}
+
+// MarshalJSON marshals the ChangeType as its name.
+func (ct ChangeType) MarshalJSON() ([]byte, error) { return json.Marshal(ct.String()) }
+
+// Change is a single difference found by Diff.
+type Change struct {
+	Type ChangeType `json:"type"`
+	// Path is the location of the change as a JSON pointer (RFC 6901), e.g. "/friends/0/name". The root is "".
+	Path string `json:"path"`
+	// Old is the old value. It is nil for ChangeAdded.
+	Old any `json:"old,omitempty"`
+	// New is the new value. It is nil for ChangeRemoved.
+	New any `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return fmt.Sprintf("%s %q: %v", c.Type, c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s %q: %v", c.Type, c.Path, c.Old)
+	default:
+		return fmt.Sprintf("%s %q: %v -> %v", c.Type, c.Path, c.Old, c.New)
+	}
+}
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	// IgnoreOrder compares slices and arrays as if they were unordered. Elements are matched to equal elements in the
+	// other slice, and any that cannot be matched are reported as ChangeAdded or ChangeRemoved, never ChangeModified.
+	IgnoreOrder bool
+	// FloatTolerance is the maximum absolute difference between two numbers, where at least one is a float, for them to
+	// be considered equal.
+	FloatTolerance float64
+	// IgnorePaths are JSON pointers to values that should not be compared. Everything beneath an ignored path is also
+	// ignored. A segment of "*" matches any key or index, e.g. "/users/*/lastSeen".
+	IgnorePaths []string
+}
+
+var (
+	jsonPointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// diffVisit identifies a pair of maps, slices, or addressable values that are currently being compared.
+type diffVisit struct {
+	a, b       uintptr
+	typ        reflect.Type
+	lenA, lenB int
+}
+
+type differ struct {
+	opts     DiffOptions
+	ignore   [][]string
+	visiting map[diffVisit]bool
+	changes  []Change
+}
+
+// Diff returns the Change(s) required to turn a into b. a and b can be any combination of nested maps, slices, arrays,
+// structs, pointers, and interfaces, such as a deserialised JSON. Maps with keys of any type are supported, and their
+// keys are formatted using fmt.Sprint within paths, or prefixed with their type if distinct keys format the same. The
+// exported fields of structs are compared, and are named using their json tag if they have one. Structs that declare
+// an Equal method that takes their own type, such as time.Time, are compared using it instead, and structs without any
+// exported fields, such as big.Int, are compared using reflect.DeepEqual.
+//
+// Numbers of different types are compared by value, so the int 1 is equal to the float64 1. Nil maps and slices are
+// equal to empty ones. Changes are returned in a deterministic order: map keys are visited in ascending order of their
+// formatted keys, and slices are visited in order of their indices.
+func Diff(a, b any, opts DiffOptions) []Change {
+	d := newDiffer(opts)
+	d.diff(nil, reflect.ValueOf(a), reflect.ValueOf(b))
+	return d.changes
+}
+
+func newDiffer(opts DiffOptions) *differ {
+	d := &differ{opts: opts, visiting: make(map[diffVisit]bool)}
+	for _, path := range opts.IgnorePaths {
+		d.ignore = append(d.ignore, splitJSONPointer(path))
+	}
+	return d
+}
+
+// splitJSONPointer splits the given JSON pointer into its unescaped segments.
+func splitJSONPointer(pointer string) []string {
+	if pointer == "" {
+		return []string{}
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = jsonPointerUnescaper.Replace(segment)
+	}
+	return segments
+}
+
+// joinJSONPointer joins the given segments into an escaped JSON pointer.
+func joinJSONPointer(segments []string) string {
+	var b strings.Builder
+	for _, segment := range segments {
+		b.WriteByte('/')
+		b.WriteString(jsonPointerEscaper.Replace(segment))
+	}
+	return b.String()
+}
+
+// ignored returns whether the given path is, or is beneath, one of the IgnorePaths.
+func (d *differ) ignored(path []string) bool {
+	for _, pattern := range d.ignore {
+		if len(pattern) > len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range pattern {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) add(changeType ChangeType, path []string, old, new reflect.Value) {
+	d.changes = append(d.changes, Change{
+		Type: changeType,
+		Path: joinJSONPointer(path),
+		Old:  interfaceOrNil(old),
+		New:  interfaceOrNil(new),
+	})
+}
+
+// interfaceOrNil returns the reflect.Value as an any, or nil if it is invalid.
+func interfaceOrNil(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// childPath returns a copy of the path with the given segment appended, so that sibling paths never share a backing
+// array.
+func childPath(path []string, segment string) []string {
+	return append(path[:len(path):len(path)], segment)
+}
+
+// indirect dereferences any pointers and interfaces. Nil pointers and interfaces become an invalid reflect.Value.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func (d *differ) diff(path []string, a, b reflect.Value) {
+	if d.ignored(path) {
+		return
+	}
+
+	a, b = indirect(a), indirect(b)
+	if visit, ok := d.visit(a, b); ok {
+		// A pair that is already being compared further up is skipped, so that cyclic values do not recurse forever
+		if d.visiting[visit] {
+			return
+		}
+		d.visiting[visit] = true
+		defer delete(d.visiting, visit)
+	}
+
+	switch {
+	case !a.IsValid() || !b.IsValid():
+		if a.IsValid() || b.IsValid() {
+			d.add(ChangeModified, path, a, b)
+		}
+		return
+	case isNumber(a) && isNumber(b):
+		if !d.numbersEqual(a, b) {
+			d.add(ChangeModified, path, a, b)
+		}
+		return
+	case a.Kind() != b.Kind():
+		d.add(ChangeModified, path, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Map:
+		d.diffMaps(path, a, b)
+	case reflect.Slice, reflect.Array:
+		if d.opts.IgnoreOrder {
+			d.diffUnordered(path, a, b)
+		} else {
+			d.diffOrdered(path, a, b)
+		}
+	case reflect.Struct:
+		if a.Type() != b.Type() {
+			d.add(ChangeModified, path, a, b)
+			return
+		}
+		if equal, ok := equalMethod(a, b); ok {
+			if !equal {
+				d.add(ChangeModified, path, a, b)
+			}
+			return
+		}
+
+		exported := 0
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			exported++
+			name := field.Name
+			if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			d.diff(childPath(path, name), a.Field(i), b.Field(i))
+		}
+
+		// Opaque structs can only be compared through their unexported fields
+		if exported == 0 && !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			d.add(ChangeModified, path, a, b)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			d.add(ChangeModified, path, a, b)
+		}
+	}
+}
+
+// visit returns the diffVisit for the given pair of dereferenced values, and whether they are a pair of maps, slices,
+// or addressable structs or arrays that could be part of a cycle. Addressable values are only reached through pointers.
+func (d *differ) visit(a, b reflect.Value) (visit diffVisit, ok bool) {
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		return visit, false
+	}
+
+	visit.typ = a.Type()
+	switch a.Kind() {
+	case reflect.Map:
+		visit.a, visit.b = a.Pointer(), b.Pointer()
+	case reflect.Slice:
+		visit.a, visit.b = a.Pointer(), b.Pointer()
+		visit.lenA, visit.lenB = a.Len(), b.Len()
+	case reflect.Struct, reflect.Array:
+		if !a.CanAddr() || !b.CanAddr() {
+			return visit, false
+		}
+		visit.a, visit.b = a.UnsafeAddr(), b.UnsafeAddr()
+	default:
+		return visit, false
+	}
+	return visit, visit.a != 0 && visit.b != 0
+}
+
+// equalMethod compares the given structs using an Equal method that takes the struct, or a pointer to it if the structs
+// are addressable. ok is false if neither method exists.
+func equalMethod(a, b reflect.Value) (equal, ok bool) {
+	call := func(a, b reflect.Value) (equal, ok bool) {
+		method := a.MethodByName("Equal")
+		if !method.IsValid() {
+			return false, false
+		}
+		t := method.Type()
+		if t.NumIn() != 1 || t.In(0) != a.Type() || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Bool {
+			return false, false
+		}
+		return method.Call([]reflect.Value{b})[0].Bool(), true
+	}
+
+	if equal, ok = call(a, b); !ok && a.CanAddr() && b.CanAddr() {
+		equal, ok = call(a.Addr(), b.Addr())
+	}
+	return
+}
+
+func (d *differ) diffMaps(path []string, a, b reflect.Value) {
+	keysA, keysB := formatKeys(a, b)
+	keys := make([]string, 0, len(keysA)+len(keysB))
+	for key := range keysA {
+		keys = append(keys, key)
+	}
+	for key := range keysB {
+		if _, ok := keysA[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyA, okA := keysA[key]
+		keyB, okB := keysB[key]
+		keyPath := childPath(path, key)
+		switch {
+		case d.ignored(keyPath):
+		case !okB:
+			d.add(ChangeRemoved, keyPath, a.MapIndex(keyA), reflect.Value{})
+		case !okA:
+			d.add(ChangeAdded, keyPath, reflect.Value{}, b.MapIndex(keyB))
+		default:
+			d.diff(keyPath, a.MapIndex(keyA), b.MapIndex(keyB))
+		}
+	}
+}
+
+// formatKeys returns the keys of the given maps, indexed by their formatted string. Keys are formatted using
+// fmt.Sprint, unless distinct keys from either map format to the same string, such as 1 and "1" in a map[any]any.
+// Those keys are prefixed with their type instead, e.g. "int(1)" and "string(1)". Keys that still collide within a
+// map, such as NaNs, are numbered.
+func formatKeys(a, b reflect.Value) (keysA, keysB map[string]reflect.Value) {
+	distinct := make(map[string][]any)
+	for _, m := range []reflect.Value{a, b} {
+		iter := m.MapRange()
+	keys:
+		for iter.Next() {
+			key := iter.Key().Interface()
+			formatted := fmt.Sprint(key)
+			for _, other := range distinct[formatted] {
+				if other == key {
+					continue keys
+				}
+			}
+			distinct[formatted] = append(distinct[formatted], key)
+		}
+	}
+
+	format := func(m reflect.Value) map[string]reflect.Value {
+		keys := make(map[string]reflect.Value, m.Len())
+		iter := m.MapRange()
+		for iter.Next() {
+			key := iter.Key().Interface()
+			formatted := fmt.Sprint(key)
+			if len(distinct[formatted]) > 1 {
+				formatted = fmt.Sprintf("%T(%v)", key, key)
+			}
+			unique := formatted
+			for n := 1; ; n++ {
+				if _, ok := keys[unique]; !ok {
+					break
+				}
+				unique = fmt.Sprintf("%s#%d", formatted, n)
+			}
+			keys[unique] = iter.Key()
+		}
+		return keys
+	}
+	return format(a), format(b)
+}
+
+func (d *differ) diffOrdered(path []string, a, b reflect.Value) {
+	for i := 0; i < a.Len() || i < b.Len(); i++ {
+		indexPath := childPath(path, strconv.Itoa(i))
+		switch {
+		case d.ignored(indexPath):
+		case i >= b.Len():
+			d.add(ChangeRemoved, indexPath, a.Index(i), reflect.Value{})
+		case i >= a.Len():
+			d.add(ChangeAdded, indexPath, reflect.Value{}, b.Index(i))
+		default:
+			d.diff(indexPath, a.Index(i), b.Index(i))
+		}
+	}
+}
+
+func (d *differ) diffUnordered(path []string, a, b reflect.Value) {
+	matched := make([]bool, b.Len())
+	for i := 0; i < a.Len(); i++ {
+		indexPath := childPath(path, strconv.Itoa(i))
+		if d.ignored(indexPath) {
+			continue
+		}
+
+		found := false
+		for j := 0; j < b.Len() && !found; j++ {
+			if !matched[j] && d.equal(indexPath, a.Index(i), b.Index(j)) {
+				matched[j], found = true, true
+			}
+		}
+		if !found {
+			d.add(ChangeRemoved, indexPath, a.Index(i), reflect.Value{})
+		}
+	}
+
+	for j := 0; j < b.Len(); j++ {
+		if indexPath := childPath(path, strconv.Itoa(j)); !matched[j] && !d.ignored(indexPath) {
+			d.add(ChangeAdded, indexPath, reflect.Value{}, b.Index(j))
+		}
+	}
+}
+
+// equal returns whether there are no changes between the two values at the given path. The pairs currently being
+// compared are shared with the sub-differ so that cycles are still detected.
+func (d *differ) equal(path []string, a, b reflect.Value) bool {
+	sub := &differ{opts: d.opts, ignore: d.ignore, visiting: d.visiting}
+	sub.diff(path, a, b)
+	return len(sub.changes) == 0
+}
+
+func isNumber(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *differ) numbersEqual(a, b reflect.Value) bool {
+	switch {
+	case a.CanInt() && b.CanInt():
+		return a.Int() == b.Int()
+	case a.CanUint() && b.CanUint():
+		return a.Uint() == b.Uint()
+	}
+
+	toFloat := func(v reflect.Value) float64 {
+		switch {
+		case v.CanInt():
+			return float64(v.Int())
+		case v.CanUint():
+			return float64(v.Uint())
+		default:
+			return v.Float()
+		}
+	}
+	fa, fb := toFloat(a), toFloat(b)
+	if math.IsNaN(fa) || math.IsNaN(fb) {
+		return math.IsNaN(fa) && math.IsNaN(fb)
+	}
+	return fa == fb || math.Abs(fa-fb) <= d.opts.FloatTolerance
+}
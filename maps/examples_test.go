@@ -1,7 +1,10 @@
 package maps
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/andygello555/gotils/v2/structs"
+	"sync"
 )
 
 // Deep copying a map using recursion via CopyMap.
@@ -38,6 +41,157 @@ func ExampleCopyMap() {
 	// Clone: map[age:20 bald:false friends:[Bob Jane John Mark map[age:31 friends:[Bill Bob Sarah] name:Gregor]] hello:world]
 }
 
+// Deep copy the contents of a structs.OrderedMap that was unmarshalled from JSON, then restore the order of its keys.
+func ExampleCopyMap_orderedMap() {
+	var om structs.OrderedMap[string, any]
+	_ = json.Unmarshal([]byte(`{"b": {"c": 1}, "a": 2}`), &om)
+
+	copied := CopyMap(om.ToMap())
+	copied["b"].(map[string]any)["c"] = 3
+
+	restored := structs.NewOrderedMap[string, any]()
+	for _, key := range om.Keys() {
+		restored.Set(key, copied[key])
+	}
+	original, _ := json.Marshal(&om)
+	modified, _ := json.Marshal(restored)
+	fmt.Println(string(original))
+	fmt.Println(string(modified))
+	// Output:
+	// {"b":{"c":1},"a":2}
+	// {"b":{"c":3},"a":2}
+}
+
+// Deep copy a struct containing nested slices, maps, and pointers. Modifying the copy does not affect the original.
+func ExampleDeepCopy() {
+	type Team struct {
+		Name    string
+		Members []string
+		Scores  map[string][]int
+		Captain *string
+	}
+
+	captain := "Bob"
+	original := Team{
+		Name:    "Blue",
+		Members: []string{"Bob", "Jane"},
+		Scores:  map[string][]int{"Bob": {1, 2}},
+		Captain: &captain,
+	}
+	clone := DeepCopy(original)
+	clone.Members[0] = "Sarah"
+	clone.Scores["Bob"][0] = 100
+	*clone.Captain = "Sarah"
+
+	fmt.Println(original.Members, original.Scores, *original.Captain)
+	fmt.Println(clone.Members, clone.Scores, *clone.Captain)
+	// Output:
+	// [Bob Jane] map[Bob:[1 2]] Bob
+	// [Sarah Jane] map[Bob:[100 2]] Sarah
+}
+
+// Cyclic values can be deep copied, and the copy will have the same cycles.
+func ExampleDeepCopy_cycle() {
+	type Node struct {
+		Value int
+		Next  *Node
+	}
+
+	a := &Node{Value: 1}
+	a.Next = &Node{Value: 2, Next: a}
+	clone := DeepCopy(a)
+	clone.Next.Value = 3
+
+	fmt.Println(a.Value, a.Next.Value, a.Next.Next == a)
+	fmt.Println(clone.Value, clone.Next.Value, clone.Next.Next == clone, clone == a)
+	// Output:
+	// 1 2 true
+	// 1 3 true false
+}
+
+// Choose what happens to unexported fields by using DeepCopyWithOptions.
+func ExampleDeepCopyWithOptions() {
+	type Account struct {
+		Name     string
+		password string
+	}
+
+	account := Account{Name: "admin", password: "hunter2"}
+	fmt.Printf("%+v\n", DeepCopy(account))
+	fmt.Printf("%+v\n", DeepCopyWithOptions(account, DeepCopyOptions{Unexported: UnexportedZero}))
+	// Output:
+	// {Name:admin password:hunter2}
+	// {Name:admin password:}
+}
+
+// client is used within ExampleCloner.
+type client struct {
+	Headers map[string]string
+	Pool    *sync.Pool
+}
+
+func (c client) Clone() any {
+	headers := make(map[string]string, len(c.Headers))
+	for key, val := range c.Headers {
+		headers[key] = val
+	}
+	return client{Headers: headers, Pool: c.Pool}
+}
+
+// Implement Cloner to control how a type is deep copied. Here, the pool is shared between the copies of a client, whilst
+// the headers are copied.
+func ExampleCloner() {
+	original := client{Headers: map[string]string{"Accept": "text/html"}, Pool: &sync.Pool{}}
+	clones := DeepCopy([]client{original, original})
+	clones[0].Headers["Accept"] = "application/json"
+
+	fmt.Println(original.Headers["Accept"], clones[0].Headers["Accept"], clones[1].Headers["Accept"])
+	fmt.Println(clones[0].Pool == original.Pool, clones[1].Pool == original.Pool)
+	// Output:
+	// text/html application/json text/html
+	// true true
+}
+
+// Audit the changes made to a deserialised JSON document.
+func ExampleDiff() {
+	var before, after map[string]any
+	_ = json.Unmarshal([]byte(`{"name": "Bob", "age": 30, "tags": ["a", "b"], "address": {"city": "Leeds"}}`), &before)
+	_ = json.Unmarshal([]byte(`{"name": "Bob", "age": 31, "tags": ["a"], "email": "bob@example.com"}`), &after)
+
+	for _, change := range Diff(before, after, DiffOptions{}) {
+		fmt.Println(change)
+	}
+	// Output:
+	// Removed "/address": map[city:Leeds]
+	// Modified "/age": 30 -> 31
+	// Added "/email": bob@example.com
+	// Removed "/tags/1": b
+}
+
+// Ignore the order of slices, small floating point errors, and any paths that are expected to change.
+func ExampleDiff_options() {
+	before := map[string]any{
+		"scores":  []float64{1.5, 2.25, 3},
+		"users":   []any{map[string]any{"name": "Bob", "lastSeen": 100}},
+		"version": 1,
+	}
+	after := map[string]any{
+		"scores":  []float64{3, 1.5, 2.2500001},
+		"users":   []any{map[string]any{"name": "Bob", "lastSeen": 200}},
+		"version": 2,
+	}
+
+	changes := Diff(before, after, DiffOptions{
+		IgnoreOrder:    true,
+		FloatTolerance: 1e-6,
+		IgnorePaths:    []string{"/users/*/lastSeen"},
+	})
+	out, _ := json.Marshal(changes)
+	fmt.Println(string(out))
+	// Output:
+	// [{"type":"Modified","path":"/version","old":1,"new":2}]
+}
+
 // Retrieve the keys from a map.
 func ExampleKeys() {
 	m := map[string]int{
@@ -82,6 +236,26 @@ func ExampleOrderedKeys() {
 	// [a b c]
 }
 
+// Retrieve the keys from a structs.LRU in order by first exporting it to a plain map.
+func ExampleOrderedKeys_cache() {
+	cache := structs.NewLRU[string, int](2, structs.CacheOptions[string, int]{})
+	cache.Set("c", 3)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	fmt.Println(OrderedKeys(cache.ToMap()))
+	// Output:
+	// [a b]
+}
+
+// Retrieve the values from a structs.Set in order. As structs.Set is a map type it can be passed to OrderedKeys
+// directly.
+func ExampleOrderedKeys_set() {
+	set := structs.NewSet("c", "a", "b")
+	fmt.Println(OrderedKeys(set))
+	// Output:
+	// [a b c]
+}
+
 // Range over a map and display the index, key, and value of the current key-value pair.
 func ExampleRangeKeys() {
 	m := map[string]int{
@@ -216,3 +390,72 @@ func ExampleDifferenceNew() {
 	// Output:
 	// map[c:3]
 }
+
+// Count words from multiple goroutines at once using a ConcurrentMap.
+func ExampleConcurrentMap() {
+	counts := NewConcurrentMap[string, int](0)
+	var wg sync.WaitGroup
+	for _, words := range [][]string{{"a", "b", "c"}, {"a", "b"}, {"a"}} {
+		wg.Add(1)
+		go func(words []string) {
+			defer wg.Done()
+			for _, word := range words {
+				counts.Compute(word, func(val int, loaded bool) (int, bool) {
+					return val + 1, true
+				})
+			}
+		}(words)
+	}
+	wg.Wait()
+
+	// Take a snapshot of the ConcurrentMap so that we can use it with the other functions in maps
+	RangeOrderedKeys(counts.Snapshot(), func(i int, key string, val int) bool {
+		fmt.Printf("%s: %d\n", key, val)
+		return true
+	})
+	// Output:
+	// a: 3
+	// b: 2
+	// c: 1
+}
+
+// Union, Filter, and find the Difference of a ConcurrentMap with other maps.
+func ExampleConcurrentMap_Filter() {
+	cm := NewConcurrentMapFrom(map[string]int{"a": 1, "b": 2, "c": 3}, 4)
+	cm.Union(map[string]int{"c": 4, "d": 5, "e": 6})
+	fmt.Println(cm.Snapshot())
+
+	cm.Filter(func(i int, key string, val int) bool {
+		return val > 2
+	})
+	fmt.Println(cm.Snapshot())
+
+	cm.Difference(map[string]int{"e": 0})
+	fmt.Println(cm.Snapshot())
+
+	actual, loaded := cm.LoadOrStore("a", 7)
+	fmt.Println(actual, loaded, cm.Len())
+	// Output:
+	// map[a:1 b:2 c:4 d:5 e:6]
+	// map[c:4 d:5 e:6]
+	// map[c:4 d:5]
+	// 7 false 3
+}
+
+// Create a ConcurrentMap with a custom hash function so that keys are not hashed using reflection.
+func ExampleNewConcurrentMapFunc() {
+	type point struct{ x, y int }
+	cm := NewConcurrentMapFunc[point, string](4, func(key point) uint64 {
+		return uint64(key.x)*31 + uint64(key.y)
+	})
+	cm.Store(point{0, 0}, "origin")
+	cm.Store(point{1, 2}, "a")
+
+	actual, loaded := cm.LoadOrStore(point{1, 2}, "b")
+	fmt.Println(actual, loaded, cm.Len())
+	val, loaded := cm.LoadAndDelete(point{0, 0})
+	fmt.Println(val, loaded, cm.Len())
+	// Output:
+	// a true 2
+	// origin true 1
+}
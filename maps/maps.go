@@ -2,28 +2,21 @@
 package maps
 
 import (
-	"container/heap"
 	"fmt"
-	"github.com/andygello555/gotils/v2/structs"
 	"github.com/go-test/deep"
 	"golang.org/x/exp/constraints"
+	"sort"
 	"strings"
 	"testing"
 )
 
-// CopyMap clones a map deeply using recursion.
+// CopyMap clones a map deeply using DeepCopy, so any maps, slices, and pointers nested within it are also copied. A nil
+// map is copied as an empty map.
 func CopyMap(m map[string]any) map[string]any {
-	cp := make(map[string]any)
-	for k, v := range m {
-		vm, ok := v.(map[string]any)
-		if ok {
-			cp[k] = CopyMap(vm)
-		} else {
-			cp[k] = v
-		}
+	if m == nil {
+		return make(map[string]any)
 	}
-
-	return cp
+	return DeepCopy(m)
 }
 
 // MapRangeFunc is the signature passed to RangeKeys, and RangeOrderedKeys. It is passed the key-value pair, and
@@ -32,20 +25,12 @@ type MapRangeFunc[K comparable, V any] func(i int, key K, val V) bool
 
 // RangeOrderedKeys calls the given MapRangeFunc on each index-key-value triple. Triples are ordered by their keys.
 func RangeOrderedKeys[K constraints.Ordered, V any](m map[K]V, fun MapRangeFunc[K, V]) {
-	keys := make(structs.Heap[K], 0)
-	heap.Init(&keys)
-	for key := range m {
-		heap.Push(&keys, key)
-	}
-
-	i := 0
-	for keys.Len() > 0 {
-		key := heap.Pop(&keys).(K)
-		val := m[key]
-		if !fun(i, key, val) {
+	keys := Keys(m)
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for i, key := range keys {
+		if !fun(i, key, m[key]) {
 			break
 		}
-		i++
 	}
 }
 
@@ -153,7 +138,7 @@ func DifferenceNew[K comparable, V any](m map[K]V, n map[K]V) map[K]V {
 
 // JsonMapEqualTest used in tests to check equality between two anys.
 //
-// This takes into account orderings of slices.
+// This takes into account orderings of slices. Use Diff to find the differences between two anys outside of tests.
 func JsonMapEqualTest(t *testing.T, actual, expected any, forString string) {
 	if diff := deep.Equal(actual, expected); diff != nil {
 		var errB strings.Builder
@@ -1,6 +1,10 @@
 package misc
 
-import "fmt"
+import (
+	"fmt"
+	"hash/maphash"
+	"math"
+)
 
 // Check if the email:
 //
@@ -33,3 +37,18 @@ func ExampleCompare() {
 	// Compare(1.23, 1.23) = Equal
 	// Compare("world", "hello") = Greater
 }
+
+// Hash some comparable values. Equal values always produce the same hash when using the same seed.
+func ExampleHash() {
+	type point struct{ x, y int }
+	seed := maphash.MakeSeed()
+	fmt.Println(Hash(seed, "hello") == Hash(seed, "hello"))
+	fmt.Println(Hash(seed, point{1, 2}) == Hash(seed, point{1, 2}))
+	fmt.Println(Hash(seed, 0.0) == Hash(seed, math.Copysign(0, -1)))
+	fmt.Println(Hash(seed, point{1, 2}) == Hash(seed, point{2, 1}))
+	// Output:
+	// true
+	// true
+	// true
+	// false
+}
@@ -2,8 +2,12 @@
 package misc
 
 import (
+	"encoding/binary"
 	"fmt"
 	"golang.org/x/exp/constraints"
+	"hash/maphash"
+	"math"
+	"reflect"
 	"regexp"
 )
 
@@ -57,3 +61,113 @@ func Compare[V constraints.Ordered](a, b V) Ordered {
 		return Greater
 	}
 }
+
+// Hash returns a 64-bit hash of the given comparable value using the given maphash.Seed. Values that are equal will
+// always produce the same hash for the same seed.
+//
+// Strings, booleans, integers, and floats are written to the maphash.Hash directly without allocating. Any other types,
+// including named types, are hashed using reflection: pointers, channels, and unsafe pointers are hashed by address,
+// whilst arrays, structs, and interfaces are hashed by their contents.
+func Hash[K comparable](seed maphash.Seed, key K) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	switch k := any(key).(type) {
+	case string:
+		h.WriteString(k)
+	case bool:
+		if k {
+			_ = h.WriteByte(1)
+		} else {
+			_ = h.WriteByte(0)
+		}
+	case int:
+		hashUint64(&h, uint64(k))
+	case int8:
+		hashUint64(&h, uint64(k))
+	case int16:
+		hashUint64(&h, uint64(k))
+	case int32:
+		hashUint64(&h, uint64(k))
+	case int64:
+		hashUint64(&h, uint64(k))
+	case uint:
+		hashUint64(&h, uint64(k))
+	case uint8:
+		hashUint64(&h, uint64(k))
+	case uint16:
+		hashUint64(&h, uint64(k))
+	case uint32:
+		hashUint64(&h, uint64(k))
+	case uint64:
+		hashUint64(&h, k)
+	case uintptr:
+		hashUint64(&h, uint64(k))
+	case float32:
+		hashFloat64(&h, float64(k))
+	case float64:
+		hashFloat64(&h, k)
+	default:
+		hashValue(&h, reflect.ValueOf(key))
+	}
+	return h.Sum64()
+}
+
+func hashUint64(h *maphash.Hash, u uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], u)
+	_, _ = h.Write(b[:])
+}
+
+func hashFloat64(h *maphash.Hash, f float64) {
+	// -0 and +0 are equal so they need to produce the same hash
+	if f == 0 {
+		f = 0
+	}
+	hashUint64(h, math.Float64bits(f))
+}
+
+// hashValue writes the given reflect.Value to the given maphash.Hash.
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		_ = h.WriteByte(0)
+	case reflect.String:
+		h.WriteString(v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			_ = h.WriteByte(1)
+		} else {
+			_ = h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		hashUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		hashUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		hashFloat64(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		hashFloat64(h, real(c))
+		hashFloat64(h, imag(c))
+	case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+		hashUint64(h, uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			_ = h.WriteByte(0)
+		} else {
+			// Values of different dynamic types are never equal, so include the type in the hash
+			h.WriteString(v.Elem().Type().String())
+			hashValue(h, v.Elem())
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			hashValue(h, v.Field(i))
+		}
+	default:
+		panic(fmt.Errorf("cannot hash value of type %s", v.Type()))
+	}
+}
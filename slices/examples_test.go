@@ -235,3 +235,42 @@ func ExampleOrder() {
 	// e: [{1} {2} {3} {4} {5}]
 	// f: [[1 2] [2 3] [3 4] [4 5] [5 6]]
 }
+
+// Squares a list of integers using 4 goroutines. The output is in the same order as the input.
+func ExampleParallelComprehension() {
+	fmt.Println(ParallelComprehension([]int{1, 2, 3, 4, 5, 6, 7, 8, 9}, 4, func(idx int, value int, arr []int) int {
+		return value * value
+	}))
+	// Output:
+	// [1 4 9 16 25 36 49 64 81]
+}
+
+// Finds the square numbers between 1 and 9 using 2 goroutines.
+func ExampleParallelFilter() {
+	fmt.Println(
+		"square numbers (0-9):",
+		ParallelFilter([]int{1, 2, 3, 4, 5, 6, 7, 8, 9}, 2, func(idx int, value int, arr []int) bool {
+			sr := int(math.Sqrt(float64(value)))
+			return sr*sr == value
+		}),
+	)
+	// Output:
+	// square numbers (0-9): [1 4 9]
+}
+
+// Checks whether any or all of the given strings are empty using as many goroutines as runtime.GOMAXPROCS(0).
+//
+// As soon as one goroutine finds a result, the others are cancelled.
+func ExampleParallelAny() {
+	strings := []string{"hello", "", "world"}
+	isEmpty := func(idx int, value string, arr []string) bool {
+		return value == ""
+	}
+	fmt.Printf("ParallelAny(%q, isEmpty) = %t\n", strings, ParallelAny(strings, 0, isEmpty))
+	fmt.Printf("ParallelAll(%q, isEmpty) = %t\n", strings, ParallelAll(strings, 0, isEmpty))
+	fmt.Printf("ParallelAll(%q) = %t\n", strings, ParallelAll(strings, 0))
+	// Output:
+	// ParallelAny(["hello" "" "world"], isEmpty) = true
+	// ParallelAll(["hello" "" "world"], isEmpty) = false
+	// ParallelAll(["hello" "" "world"]) = false
+}
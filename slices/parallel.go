@@ -0,0 +1,135 @@
+package slices
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// parallelChunks splits the given slice into at most the given number of contiguous chunks, and calls fun on each
+// chunk within its own goroutine. The start and end indices of the chunk are passed to fun along with the number of the
+// chunk. parallelChunks will wait for all goroutines to finish before returning the number of chunks.
+//
+// If workers <= 0 then runtime.GOMAXPROCS(0) workers are used.
+func parallelChunks[E any](s []E, workers int, fun func(chunk, start, end int)) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(s) {
+		workers = len(s)
+	}
+	if workers == 0 {
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	size, remainder := len(s)/workers, len(s)%workers
+	start := 0
+	for chunk := 0; chunk < workers; chunk++ {
+		// The first "remainder" chunks take an extra element so that all elements are covered
+		end := start + size
+		if chunk < remainder {
+			end++
+		}
+		go func(chunk, start, end int) {
+			defer wg.Done()
+			fun(chunk, start, end)
+		}(chunk, start, end)
+		start = end
+	}
+	wg.Wait()
+	return workers
+}
+
+// ParallelComprehension works similarly to Comprehension, except the given slice is split into chunks that are each
+// processed within their own goroutine. The order of the output is identical to that of Comprehension.
+//
+// If workers <= 0 then runtime.GOMAXPROCS(0) goroutines are used. The given function should be safe to call from
+// multiple goroutines at once.
+func ParallelComprehension[IE any, OE any](s []IE, workers int, fun func(idx int, value IE, arr []IE) OE) []OE {
+	out := make([]OE, len(s))
+	parallelChunks(s, workers, func(chunk, start, end int) {
+		for i := start; i < end; i++ {
+			out[i] = fun(i, s[i], s)
+		}
+	})
+	return out
+}
+
+// ParallelFilter works similarly to Filter, except the given slice is split into chunks that are each processed within
+// their own goroutine. The order of the output is identical to that of Filter.
+//
+// If workers <= 0 then runtime.GOMAXPROCS(0) goroutines are used. The given predicate should be safe to call from
+// multiple goroutines at once.
+func ParallelFilter[E any](s []E, workers int, fun func(idx int, value E, arr []E) bool) []E {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	filtered := make([][]E, workers)
+	chunks := parallelChunks(s, workers, func(chunk, start, end int) {
+		filtered[chunk] = make([]E, 0, end-start)
+		for i := start; i < end; i++ {
+			if fun(i, s[i], s) {
+				filtered[chunk] = append(filtered[chunk], s[i])
+			}
+		}
+	})
+	return Join(filtered[:chunks]...)
+}
+
+// parallelShortCircuit runs the given function on each element of the given slice in parallel, cancelling all
+// goroutines once one of the function calls returns the given short value. Returns whether short was returned.
+func parallelShortCircuit[E any](s []E, workers int, short bool, funcs []func(idx int, value E, arr []E) bool) bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	parallelChunks(s, workers, func(chunk, start, end int) {
+		for i := start; i < end; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			if funcs[i%len(funcs)](i, s[i], s) == short {
+				cancel()
+				return
+			}
+		}
+	})
+	return ctx.Err() != nil
+}
+
+// ParallelAny works similarly to Any, except the given slice is split into chunks that are each processed within their
+// own goroutine. Once an element is found to be truthy, all goroutines are cancelled and ParallelAny will return true.
+//
+// If workers <= 0 then runtime.GOMAXPROCS(0) goroutines are used. The given functions should be safe to call from
+// multiple goroutines at once.
+func ParallelAny[E any](s []E, workers int, funcs ...func(idx int, value E, arr []E) bool) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	if len(funcs) == 0 {
+		funcs = emptyFuncsResolve[E]()
+	}
+	return parallelShortCircuit(s, workers, true, funcs)
+}
+
+// ParallelAll works similarly to All, except the given slice is split into chunks that are each processed within their
+// own goroutine. Once an element is found not to be truthy, all goroutines are cancelled and ParallelAll will return
+// false.
+//
+// If workers <= 0 then runtime.GOMAXPROCS(0) goroutines are used. The given functions should be safe to call from
+// multiple goroutines at once.
+func ParallelAll[E any](s []E, workers int, funcs ...func(idx int, value E, arr []E) bool) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	if len(funcs) == 0 {
+		funcs = emptyFuncsResolve[E]()
+	}
+	return !parallelShortCircuit(s, workers, false, funcs)
+}
@@ -2,6 +2,8 @@ package strings
 
 import (
 	"fmt"
+	"github.com/andygello555/gotils/v2/structs"
+	"strings"
 )
 
 // Will replace the given character indices within the given string with the given new strings in the order given.
@@ -63,6 +65,26 @@ func ExampleSplitCamelcase() {
 	// Output: [Hello World]
 }
 
+// Index some identifiers by their camelcase words using a structs.Trie, so that they can be autocompleted from the
+// start of any of their words.
+func ExampleSplitCamelcase_trie() {
+	index := structs.NewTrie[[]string]()
+	for _, identifier := range []string{"HttpServer", "HttpClient", "ServerConfig", "ClientError"} {
+		for _, word := range SplitCamelcase(identifier) {
+			identifiers, _ := index.Get(strings.ToLower(word))
+			index.Set(strings.ToLower(word), append(identifiers, identifier))
+		}
+	}
+
+	index.RangePrefix("c", func(i int, word string, identifiers []string) bool {
+		fmt.Println(word, identifiers)
+		return true
+	})
+	// Output:
+	// client [HttpClient ClientError]
+	// config [ServerConfig]
+}
+
 // Joins each hump of the camelcase string with the given separator.
 func ExampleJoinCamelcase() {
 	fmt.Println(JoinCamelcase("HelloWorld", ", "))
@@ -4,6 +4,7 @@ package strings
 import (
 	"bytes"
 	"github.com/andygello555/gotils/v2/slices"
+	"github.com/andygello555/gotils/v2/structs"
 	"reflect"
 	"sort"
 	"strings"
@@ -70,23 +71,22 @@ func ReplaceCharIndex(old string, indices []int, new ...string) string {
 // The length of new strings must be less than or equal to the length of the indices slice. The length of indices must
 // also be greater than 0. If any of these conditions are not met the old string shall be returned.
 //
-// The indices slice can contain duplicates and doesn't need to be sorted. It's worth bearing in mind that removing the
-// duplicates from the indices slice is O(n^2).
+// The indices slice can contain duplicates and doesn't need to be sorted. Ranges that overlap a range which comes
+// before them in the indices slice, including duplicates, are skipped, as are ranges that do not contain any indices.
 func ReplaceCharIndexRange(old string, indices [][]int, new ...string) string {
 	if len(indices) > 0 && len(new) <= len(indices) {
-		// Remove duplicates from the indices slice
-		// FIXME: Find a more efficient way of doing this. Wrapper for 2D []int with equality?
+		// Remove overlapping ranges and duplicates from the indices slice
+		accepted := structs.NewIntervalTree[int, struct{}]()
 		newIndices := make([][]int, 0)
 		for _, ran := range indices {
-			for _, inSet := range newIndices {
-				if ran[0] == inSet[0] && ran[1] == inSet[1] {
-					// We continue onto the next element in indices without appending to newIndices
-					goto skip
-				}
+			if ran[1] <= ran[0] || accepted.Overlaps(ran[0], ran[1]-1) {
+				continue
 			}
+			accepted.Insert(ran[0], ran[1]-1, struct{}{})
 			newIndices = append(newIndices, ran)
-		skip:
-			continue
+		}
+		if len(newIndices) == 0 {
+			return old
 		}
 		indices = newIndices
 
@@ -0,0 +1,342 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+// hashPair hashes the given data into two 64-bit hashes. The hashes are combined using double hashing to derive the
+// location of the data within each of the k hash functions of a probabilistic structure. FNV-1a is used rather than
+// hash/maphash so that hashes are stable across processes, and structures can be persisted and reloaded.
+func hashPair(data []byte) (h1, h2 uint64) {
+	h := fnv.New128a()
+	_, _ = h.Write(data)
+	sum := h.Sum(nil)
+	h1 = binary.BigEndian.Uint64(sum[:8])
+	// h2 must be odd so that it never cycles through only a subset of locations
+	h2 = binary.BigEndian.Uint64(sum[8:]) | 1
+	return
+}
+
+// location returns the location of the data in the ith hash function out of m locations.
+func location(h1, h2 uint64, i int, m uint64) uint64 { return (h1 + uint64(i)*h2) % m }
+
+// optimalBloomSize returns the optimal number of locations, m, and hash functions, k, for a Bloom filter that will
+// contain n items with the given false positive rate p.
+func optimalBloomSize(n uint64, p float64) (m uint64, k int) {
+	if n == 0 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		panic(fmt.Errorf("false positive rate must be between 0 and 1, not %v", p))
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = int(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return
+}
+
+// binaryHeader is written at the start of the binary encoding of each probabilistic structure.
+type binaryHeader struct {
+	Magic [4]byte
+	M     uint64
+	K     uint64
+	N     uint64
+}
+
+// marshalBinary encodes the given header followed by the given data.
+func marshalBinary(header binaryHeader, data any) ([]byte, error) {
+	var b bytes.Buffer
+	if err := binary.Write(&b, binary.BigEndian, header); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(&b, binary.BigEndian, data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// unmarshalBinary decodes a header with the given magic, then uses the given function to allocate the data that is
+// decoded after the header.
+func unmarshalBinary(
+	data []byte,
+	magic string,
+	alloc func(header binaryHeader) (any, error),
+) (header binaryHeader, err error) {
+	r := bytes.NewReader(data)
+	if err = binary.Read(r, binary.BigEndian, &header); err != nil {
+		return header, fmt.Errorf("cannot read header: %w", err)
+	}
+	if string(header.Magic[:]) != magic {
+		return header, fmt.Errorf("expected magic %q, got %q", magic, header.Magic[:])
+	}
+
+	var into any
+	if into, err = alloc(header); err != nil {
+		return
+	}
+	if size := binary.Size(into); size != r.Len() {
+		return header, fmt.Errorf("expected %d bytes of data, got %d", size, r.Len())
+	}
+	if err = binary.Read(r, binary.BigEndian, into); err != nil {
+		return header, fmt.Errorf("cannot read data: %w", err)
+	}
+	return
+}
+
+const bloomFilterMagic = "BLMF"
+
+// BloomFilter is a space-efficient probabilistic set. Contains never returns false for data that has been added, but
+// may return true for data that has not been added, with a probability that is given when the BloomFilter is created.
+// Data cannot be removed from a BloomFilter; see CountingBloomFilter for that.
+//
+// BloomFilter implements encoding.BinaryMarshaler and encoding.BinaryUnmarshaler so that it can be persisted.
+//
+// BloomFilter is not safe for concurrent use.
+type BloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    int
+	n    uint64
+}
+
+// NewBloomFilter creates a new BloomFilter that is sized to contain n items with the given false positive rate.
+// NewBloomFilter panics if the false positive rate is not between 0 and 1.
+func NewBloomFilter(n uint64, falsePositiveRate float64) *BloomFilter {
+	m, k := optimalBloomSize(n, falsePositiveRate)
+	return NewBloomFilterSize(m, k)
+}
+
+// NewBloomFilterSize creates a new BloomFilter with m bits and k hash functions. NewBloomFilterSize panics if m or k
+// are less than 1, or if there are more hash functions than bits.
+func NewBloomFilterSize(m uint64, k int) *BloomFilter {
+	if m < 1 || k < 1 || uint64(k) > m {
+		panic(fmt.Errorf("bloom filter must have at least 1 bit and between 1 and %d hash functions, not %d", m, k))
+	}
+	return &BloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// Bits returns the number of bits in the BloomFilter.
+func (bf *BloomFilter) Bits() uint64 { return bf.m }
+
+// HashFunctions returns the number of hash functions used by the BloomFilter.
+func (bf *BloomFilter) HashFunctions() int { return bf.k }
+
+// Count returns the number of times that data has been added to the BloomFilter.
+func (bf *BloomFilter) Count() uint64 { return bf.n }
+
+// Add adds the given data to the BloomFilter.
+func (bf *BloomFilter) Add(data []byte) {
+	h1, h2 := hashPair(data)
+	for i := 0; i < bf.k; i++ {
+		loc := location(h1, h2, i, bf.m)
+		bf.bits[loc/64] |= 1 << (loc % 64)
+	}
+	bf.n++
+}
+
+// AddString adds the given string to the BloomFilter.
+func (bf *BloomFilter) AddString(s string) { bf.Add([]byte(s)) }
+
+// Contains returns whether the given data might have been added to the BloomFilter. If Contains returns false then the
+// data has definitely not been added.
+func (bf *BloomFilter) Contains(data []byte) bool {
+	h1, h2 := hashPair(data)
+	for i := 0; i < bf.k; i++ {
+		loc := location(h1, h2, i, bf.m)
+		if bf.bits[loc/64]&(1<<(loc%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsString returns whether the given string might have been added to the BloomFilter.
+func (bf *BloomFilter) ContainsString(s string) bool { return bf.Contains([]byte(s)) }
+
+// FalsePositiveRate estimates the current false positive rate of the BloomFilter from the number of items that have
+// been added.
+func (bf *BloomFilter) FalsePositiveRate() float64 {
+	return math.Pow(1-math.Exp(-float64(bf.k)*float64(bf.n)/float64(bf.m)), float64(bf.k))
+}
+
+// Union adds all the data within the other BloomFilter to the BloomFilter. Both BloomFilter(s) must have the same
+// number of bits and hash functions.
+func (bf *BloomFilter) Union(other *BloomFilter) error {
+	if bf.m != other.m || bf.k != other.k {
+		return fmt.Errorf(
+			"cannot union bloom filters of different sizes (%d, %d) and (%d, %d)",
+			bf.m, bf.k, other.m, other.k,
+		)
+	}
+	for i := range bf.bits {
+		bf.bits[i] |= other.bits[i]
+	}
+	bf.n += other.n
+	return nil
+}
+
+// Clear removes all data from the BloomFilter.
+func (bf *BloomFilter) Clear() {
+	for i := range bf.bits {
+		bf.bits[i] = 0
+	}
+	bf.n = 0
+}
+
+// MarshalBinary encodes the BloomFilter into a binary form.
+func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
+	header := binaryHeader{M: bf.m, K: uint64(bf.k), N: bf.n}
+	copy(header.Magic[:], bloomFilterMagic)
+	return marshalBinary(header, bf.bits)
+}
+
+// UnmarshalBinary decodes a BloomFilter from the binary form produced by MarshalBinary.
+func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
+	var bits []uint64
+	header, err := unmarshalBinary(data, bloomFilterMagic, func(header binaryHeader) (any, error) {
+		// K is bounded by M, which is bounded by the length of the data, so that it always fits within an int
+		if header.M < 1 || header.K < 1 || header.K > header.M || header.M > uint64(len(data))*8 {
+			return nil, fmt.Errorf("invalid bloom filter size (%d, %d)", header.M, header.K)
+		}
+		bits = make([]uint64, (header.M+63)/64)
+		return bits, nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal BloomFilter: %w", err)
+	}
+	*bf = BloomFilter{bits: bits, m: header.M, k: int(header.K), n: header.N}
+	return nil
+}
+
+const countingBloomFilterMagic = "CBLM"
+
+// CountingBloomFilter is a BloomFilter that supports removal. Each location holds an 8-bit counter rather than a single
+// bit, so it uses 8 times as much memory as a BloomFilter of the same size. Counters saturate at 255, after which they
+// are never decremented so that false negatives cannot occur.
+//
+// CountingBloomFilter implements encoding.BinaryMarshaler and encoding.BinaryUnmarshaler so that it can be persisted.
+//
+// CountingBloomFilter is not safe for concurrent use.
+type CountingBloomFilter struct {
+	counters []uint8
+	k        int
+	n        uint64
+}
+
+// NewCountingBloomFilter creates a new CountingBloomFilter that is sized to contain n items with the given false
+// positive rate. NewCountingBloomFilter panics if the false positive rate is not between 0 and 1.
+func NewCountingBloomFilter(n uint64, falsePositiveRate float64) *CountingBloomFilter {
+	m, k := optimalBloomSize(n, falsePositiveRate)
+	return NewCountingBloomFilterSize(m, k)
+}
+
+// NewCountingBloomFilterSize creates a new CountingBloomFilter with m counters and k hash functions.
+// NewCountingBloomFilterSize panics if m or k are less than 1, or if there are more hash functions than counters.
+func NewCountingBloomFilterSize(m uint64, k int) *CountingBloomFilter {
+	if m < 1 || k < 1 || uint64(k) > m {
+		panic(fmt.Errorf("bloom filter must have at least 1 counter and between 1 and %d hash functions, not %d", m, k))
+	}
+	return &CountingBloomFilter{counters: make([]uint8, m), k: k}
+}
+
+// Counters returns the number of counters in the CountingBloomFilter.
+func (cbf *CountingBloomFilter) Counters() uint64 { return uint64(len(cbf.counters)) }
+
+// HashFunctions returns the number of hash functions used by the CountingBloomFilter.
+func (cbf *CountingBloomFilter) HashFunctions() int { return cbf.k }
+
+// Count returns the number of items within the CountingBloomFilter, i.e. the number of times that data has been added
+// minus the number of times that data has been removed.
+func (cbf *CountingBloomFilter) Count() uint64 { return cbf.n }
+
+// Add adds the given data to the CountingBloomFilter.
+func (cbf *CountingBloomFilter) Add(data []byte) {
+	h1, h2 := hashPair(data)
+	m := uint64(len(cbf.counters))
+	for i := 0; i < cbf.k; i++ {
+		if loc := location(h1, h2, i, m); cbf.counters[loc] < math.MaxUint8 {
+			cbf.counters[loc]++
+		}
+	}
+	cbf.n++
+}
+
+// AddString adds the given string to the CountingBloomFilter.
+func (cbf *CountingBloomFilter) AddString(s string) { cbf.Add([]byte(s)) }
+
+// Contains returns whether the given data might be within the CountingBloomFilter. If Contains returns false then the
+// data is definitely not within the CountingBloomFilter.
+func (cbf *CountingBloomFilter) Contains(data []byte) bool {
+	h1, h2 := hashPair(data)
+	m := uint64(len(cbf.counters))
+	for i := 0; i < cbf.k; i++ {
+		if cbf.counters[location(h1, h2, i, m)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsString returns whether the given string might be within the CountingBloomFilter.
+func (cbf *CountingBloomFilter) ContainsString(s string) bool { return cbf.Contains([]byte(s)) }
+
+// Remove removes the given data from the CountingBloomFilter. Data that is not within the CountingBloomFilter, as
+// reported by Contains, is not removed. Returns whether the data was removed.
+//
+// Only data that has been added should be removed. Removing data that has not been added, but that is a false positive,
+// will cause false negatives for other data.
+func (cbf *CountingBloomFilter) Remove(data []byte) bool {
+	if !cbf.Contains(data) {
+		return false
+	}
+	h1, h2 := hashPair(data)
+	m := uint64(len(cbf.counters))
+	for i := 0; i < cbf.k; i++ {
+		if loc := location(h1, h2, i, m); cbf.counters[loc] < math.MaxUint8 {
+			cbf.counters[loc]--
+		}
+	}
+	cbf.n--
+	return true
+}
+
+// RemoveString removes the given string from the CountingBloomFilter. Returns whether the string was removed.
+func (cbf *CountingBloomFilter) RemoveString(s string) bool { return cbf.Remove([]byte(s)) }
+
+// Clear removes all data from the CountingBloomFilter.
+func (cbf *CountingBloomFilter) Clear() {
+	for i := range cbf.counters {
+		cbf.counters[i] = 0
+	}
+	cbf.n = 0
+}
+
+// MarshalBinary encodes the CountingBloomFilter into a binary form.
+func (cbf *CountingBloomFilter) MarshalBinary() ([]byte, error) {
+	header := binaryHeader{M: uint64(len(cbf.counters)), K: uint64(cbf.k), N: cbf.n}
+	copy(header.Magic[:], countingBloomFilterMagic)
+	return marshalBinary(header, cbf.counters)
+}
+
+// UnmarshalBinary decodes a CountingBloomFilter from the binary form produced by MarshalBinary.
+func (cbf *CountingBloomFilter) UnmarshalBinary(data []byte) error {
+	var counters []uint8
+	header, err := unmarshalBinary(data, countingBloomFilterMagic, func(header binaryHeader) (any, error) {
+		if header.M < 1 || header.K < 1 || header.K > header.M || header.M > uint64(len(data)) {
+			return nil, fmt.Errorf("invalid counting bloom filter size (%d, %d)", header.M, header.K)
+		}
+		counters = make([]uint8, header.M)
+		return counters, nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal CountingBloomFilter: %w", err)
+	}
+	*cbf = CountingBloomFilter{counters: counters, k: int(header.K), n: header.N}
+	return nil
+}
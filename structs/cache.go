@@ -0,0 +1,477 @@
+package structs
+
+import (
+	"sort"
+	"time"
+)
+
+// EvictReason is the reason that an entry was evicted from a cache.
+type EvictReason int
+
+const (
+	// EvictCapacity is given when an entry is evicted to make room for a new entry.
+	EvictCapacity EvictReason = iota
+	// EvictExpired is given when an entry is removed because its TTL has passed.
+	EvictExpired
+)
+
+func (er EvictReason) String() string {
+	switch er {
+	case EvictCapacity:
+		return "Capacity"
+	case EvictExpired:
+		return "Expired"
+	default:
+		return "Unknown"
+	}
+}
+
+// CacheOptions configures an LRU or an LFU.
+type CacheOptions[K comparable, V any] struct {
+	// OnEvict is called for every entry that is evicted because the cache is full, or that is removed because it has
+	// expired. It is not called for entries that are deleted or purged.
+	OnEvict func(key K, val V, reason EvictReason)
+	// TTL is the time-to-live that is given to entries added using Set. If TTL <= 0 then these entries never expire.
+	TTL time.Duration
+	// Now returns the current time, and is used to expire entries. If Now is nil then time.Now is used.
+	Now func() time.Time
+}
+
+func (o CacheOptions[K, V]) now() time.Time {
+	if o.Now == nil {
+		return time.Now()
+	}
+	return o.Now()
+}
+
+func (o CacheOptions[K, V]) evict(key K, val V, reason EvictReason) {
+	if o.OnEvict != nil {
+		o.OnEvict(key, val, reason)
+	}
+}
+
+// evictReason returns the EvictReason for evicting the given entry to make room for a new entry. Entries that have
+// already expired are reported as EvictExpired.
+func evictReason[K comparable, V any](e *cacheEntry[K, V], now time.Time) EvictReason {
+	if e.expired(now) {
+		return EvictExpired
+	}
+	return EvictCapacity
+}
+
+// cacheEntry is an entry within an LRU or an LFU. Entries are linked together in an intrusive doubly linked list.
+type cacheEntry[K comparable, V any] struct {
+	key        K
+	val        V
+	expires    time.Time
+	freq       int
+	prev, next *cacheEntry[K, V]
+}
+
+func (e *cacheEntry[K, V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// entryList is a circular doubly linked list of cacheEntry(s) with a sentinel root. The front of the list is the most
+// recently used entry.
+type entryList[K comparable, V any] struct {
+	root   cacheEntry[K, V]
+	length int
+}
+
+func newEntryList[K comparable, V any]() *entryList[K, V] {
+	l := &entryList[K, V]{}
+	l.root.next, l.root.prev = &l.root, &l.root
+	return l
+}
+
+func (l *entryList[K, V]) pushFront(e *cacheEntry[K, V]) {
+	e.prev, e.next = &l.root, l.root.next
+	l.root.next.prev = e
+	l.root.next = e
+	l.length++
+}
+
+func (l *entryList[K, V]) remove(e *cacheEntry[K, V]) {
+	e.prev.next, e.next.prev = e.next, e.prev
+	e.prev, e.next = nil, nil
+	l.length--
+}
+
+func (l *entryList[K, V]) moveToFront(e *cacheEntry[K, V]) {
+	l.remove(e)
+	l.pushFront(e)
+}
+
+// back returns the least recently used entry, or nil if the list is empty.
+func (l *entryList[K, V]) back() *cacheEntry[K, V] {
+	if l.length == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// LRU is a fixed capacity cache that evicts the least recently used entry once it is full. Set, Get, Peek, and
+// Delete are all O(1).
+//
+// LRU is not safe for concurrent use.
+type LRU[K comparable, V any] struct {
+	capacity int
+	opts     CacheOptions[K, V]
+	entries  map[K]*cacheEntry[K, V]
+	order    *entryList[K, V]
+}
+
+// NewLRU creates a new LRU that can hold up to the given number of entries. If capacity <= 0 then the LRU can grow
+// without bound.
+func NewLRU[K comparable, V any](capacity int, opts CacheOptions[K, V]) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		opts:     opts,
+		entries:  make(map[K]*cacheEntry[K, V]),
+		order:    newEntryList[K, V](),
+	}
+}
+
+// Set adds the given key-value pair to the LRU, using the TTL from the CacheOptions. If the key already exists then its
+// value is replaced. If the LRU is full then the least recently used entry is evicted.
+func (c *LRU[K, V]) Set(key K, val V) { c.SetWithTTL(key, val, c.opts.TTL) }
+
+// SetWithTTL works in the same way as Set but gives the entry the given TTL. If ttl <= 0 then the entry never expires.
+func (c *LRU[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.opts.now().Add(ttl)
+	}
+
+	if e, ok := c.entries[key]; ok {
+		e.val, e.expires = val, expires
+		c.order.moveToFront(e)
+		return
+	}
+
+	if c.capacity > 0 && c.order.length >= c.capacity {
+		e := c.order.back()
+		c.remove(e)
+		c.opts.evict(e.key, e.val, evictReason(e, c.opts.now()))
+	}
+
+	e := &cacheEntry[K, V]{key: key, val: val, expires: expires}
+	c.entries[key] = e
+	c.order.pushFront(e)
+}
+
+// lookup returns the entry for the given key, removing it if it has expired.
+func (c *LRU[K, V]) lookup(key K) (*cacheEntry[K, V], bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if e.expired(c.opts.now()) {
+		c.remove(e)
+		c.opts.evict(e.key, e.val, EvictExpired)
+		return nil, false
+	}
+	return e, true
+}
+
+func (c *LRU[K, V]) remove(e *cacheEntry[K, V]) {
+	c.order.remove(e)
+	delete(c.entries, e.key)
+}
+
+// Get returns the value for the given key and marks it as the most recently used entry.
+func (c *LRU[K, V]) Get(key K) (val V, ok bool) {
+	e, ok := c.lookup(key)
+	if !ok {
+		return
+	}
+	c.order.moveToFront(e)
+	return e.val, true
+}
+
+// Peek returns the value for the given key without marking it as recently used.
+func (c *LRU[K, V]) Peek(key K) (val V, ok bool) {
+	e, ok := c.lookup(key)
+	if !ok {
+		return
+	}
+	return e.val, true
+}
+
+// Contains returns whether the given key exists in the LRU, without marking it as recently used.
+func (c *LRU[K, V]) Contains(key K) bool {
+	_, ok := c.lookup(key)
+	return ok
+}
+
+// Delete removes the given key from the LRU. Returns whether the key existed.
+func (c *LRU[K, V]) Delete(key K) bool {
+	e, ok := c.entries[key]
+	if ok {
+		c.remove(e)
+	}
+	return ok
+}
+
+// removeExpired removes all the entries that have expired by the given time.
+func (c *LRU[K, V]) removeExpired(now time.Time) {
+	for e := c.order.root.next; e != &c.order.root; {
+		next := e.next
+		if e.expired(now) {
+			c.remove(e)
+			c.opts.evict(e.key, e.val, EvictExpired)
+		}
+		e = next
+	}
+}
+
+// RemoveExpired removes all the entries that have expired.
+func (c *LRU[K, V]) RemoveExpired() { c.removeExpired(c.opts.now()) }
+
+// Len returns the number of entries in the LRU, including any that have expired but have not yet been removed.
+func (c *LRU[K, V]) Len() int { return c.order.length }
+
+// Cap returns the capacity of the LRU.
+func (c *LRU[K, V]) Cap() int { return c.capacity }
+
+// Keys returns the keys of the entries that have not expired, from the most to the least recently used.
+func (c *LRU[K, V]) Keys() []K {
+	c.RemoveExpired()
+	keys := make([]K, 0, c.order.length)
+	for e := c.order.root.next; e != &c.order.root; e = e.next {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// ToMap returns a plain map containing the entries that have not expired. This can be used with the functions in the
+// maps package, such as maps.OrderedKeys.
+func (c *LRU[K, V]) ToMap() map[K]V {
+	c.RemoveExpired()
+	m := make(map[K]V, c.order.length)
+	for key, e := range c.entries {
+		m[key] = e.val
+	}
+	return m
+}
+
+// Purge removes all entries from the LRU without calling CacheOptions.OnEvict.
+func (c *LRU[K, V]) Purge() {
+	c.entries = make(map[K]*cacheEntry[K, V])
+	c.order = newEntryList[K, V]()
+}
+
+// LFU is a fixed capacity cache that evicts the least frequently used entry once it is full. If multiple entries are
+// equally infrequently used then the least recently used of them is evicted. Set, Get, Peek, and Delete are all O(1).
+//
+// LFU is not safe for concurrent use.
+type LFU[K comparable, V any] struct {
+	capacity int
+	opts     CacheOptions[K, V]
+	entries  map[K]*cacheEntry[K, V]
+	// freqs maps each frequency to the list of entries that have been used that many times
+	freqs   map[int]*entryList[K, V]
+	minFreq int
+}
+
+// NewLFU creates a new LFU that can hold up to the given number of entries. If capacity <= 0 then the LFU can grow
+// without bound.
+func NewLFU[K comparable, V any](capacity int, opts CacheOptions[K, V]) *LFU[K, V] {
+	return &LFU[K, V]{
+		capacity: capacity,
+		opts:     opts,
+		entries:  make(map[K]*cacheEntry[K, V]),
+		freqs:    make(map[int]*entryList[K, V]),
+	}
+}
+
+func (c *LFU[K, V]) list(freq int) *entryList[K, V] {
+	l, ok := c.freqs[freq]
+	if !ok {
+		l = newEntryList[K, V]()
+		c.freqs[freq] = l
+	}
+	return l
+}
+
+func (c *LFU[K, V]) remove(e *cacheEntry[K, V]) {
+	l := c.freqs[e.freq]
+	l.remove(e)
+	if l.length == 0 {
+		delete(c.freqs, e.freq)
+	}
+	delete(c.entries, e.key)
+}
+
+// touch increments the frequency of the given entry.
+func (c *LFU[K, V]) touch(e *cacheEntry[K, V]) {
+	l := c.freqs[e.freq]
+	l.remove(e)
+	if l.length == 0 {
+		delete(c.freqs, e.freq)
+		if c.minFreq == e.freq {
+			c.minFreq++
+		}
+	}
+	e.freq++
+	c.list(e.freq).pushFront(e)
+}
+
+// Set adds the given key-value pair to the LFU, using the TTL from the CacheOptions. If the key already exists then its
+// value is replaced and its frequency is incremented. If the LFU is full then the least frequently used entry is
+// evicted.
+func (c *LFU[K, V]) Set(key K, val V) { c.SetWithTTL(key, val, c.opts.TTL) }
+
+// SetWithTTL works in the same way as Set but gives the entry the given TTL. If ttl <= 0 then the entry never expires.
+func (c *LFU[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = c.opts.now().Add(ttl)
+	}
+
+	if e, ok := c.entries[key]; ok {
+		e.val, e.expires = val, expires
+		c.touch(e)
+		return
+	}
+
+	if c.capacity > 0 && len(c.entries) >= c.capacity {
+		c.fixMinFreq()
+		e := c.freqs[c.minFreq].back()
+		c.remove(e)
+		c.opts.evict(e.key, e.val, evictReason(e, c.opts.now()))
+	}
+
+	e := &cacheEntry[K, V]{key: key, val: val, expires: expires, freq: 1}
+	c.entries[key] = e
+	c.list(1).pushFront(e)
+	c.minFreq = 1
+}
+
+// lookup returns the entry for the given key, removing it if it has expired.
+func (c *LFU[K, V]) lookup(key K) (*cacheEntry[K, V], bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if e.expired(c.opts.now()) {
+		c.remove(e)
+		c.opts.evict(e.key, e.val, EvictExpired)
+		return nil, false
+	}
+	return e, true
+}
+
+// Get returns the value for the given key and increments its frequency.
+func (c *LFU[K, V]) Get(key K) (val V, ok bool) {
+	e, ok := c.lookup(key)
+	if !ok {
+		return
+	}
+	c.touch(e)
+	return e.val, true
+}
+
+// Peek returns the value for the given key without incrementing its frequency.
+func (c *LFU[K, V]) Peek(key K) (val V, ok bool) {
+	e, ok := c.lookup(key)
+	if !ok {
+		return
+	}
+	return e.val, true
+}
+
+// Contains returns whether the given key exists in the LFU, without incrementing its frequency.
+func (c *LFU[K, V]) Contains(key K) bool {
+	_, ok := c.lookup(key)
+	return ok
+}
+
+// Frequency returns the number of times that the given key has been set or retrieved using Get. Returns 0 if the key
+// does not exist.
+func (c *LFU[K, V]) Frequency(key K) int {
+	if e, ok := c.lookup(key); ok {
+		return e.freq
+	}
+	return 0
+}
+
+// Delete removes the given key from the LFU. Returns whether the key existed.
+func (c *LFU[K, V]) Delete(key K) bool {
+	e, ok := c.entries[key]
+	if ok {
+		c.remove(e)
+	}
+	return ok
+}
+
+// removeExpired removes all the entries that have expired by the given time.
+func (c *LFU[K, V]) removeExpired(now time.Time) {
+	for _, e := range c.entries {
+		if e.expired(now) {
+			c.remove(e)
+			c.opts.evict(e.key, e.val, EvictExpired)
+		}
+	}
+}
+
+// fixMinFreq recalculates the minimum frequency if all the entries with the minimum frequency have been removed. This
+// only happens when entries are deleted or expire, so eviction stays O(1) in the common case.
+func (c *LFU[K, V]) fixMinFreq() {
+	if _, ok := c.freqs[c.minFreq]; ok {
+		return
+	}
+	c.minFreq = 0
+	for freq := range c.freqs {
+		if c.minFreq == 0 || freq < c.minFreq {
+			c.minFreq = freq
+		}
+	}
+}
+
+// RemoveExpired removes all the entries that have expired.
+func (c *LFU[K, V]) RemoveExpired() { c.removeExpired(c.opts.now()) }
+
+// Len returns the number of entries in the LFU, including any that have expired but have not yet been removed.
+func (c *LFU[K, V]) Len() int { return len(c.entries) }
+
+// Cap returns the capacity of the LFU.
+func (c *LFU[K, V]) Cap() int { return c.capacity }
+
+// Keys returns the keys of the entries that have not expired, from the most to the least frequently used.
+func (c *LFU[K, V]) Keys() []K {
+	c.RemoveExpired()
+	freqs := make([]int, 0, len(c.freqs))
+	for freq := range c.freqs {
+		freqs = append(freqs, freq)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(freqs)))
+
+	keys := make([]K, 0, len(c.entries))
+	for _, freq := range freqs {
+		l := c.freqs[freq]
+		for e := l.root.next; e != &l.root; e = e.next {
+			keys = append(keys, e.key)
+		}
+	}
+	return keys
+}
+
+// ToMap returns a plain map containing the entries that have not expired. This can be used with the functions in the
+// maps package, such as maps.OrderedKeys.
+func (c *LFU[K, V]) ToMap() map[K]V {
+	c.RemoveExpired()
+	m := make(map[K]V, len(c.entries))
+	for key, e := range c.entries {
+		m[key] = e.val
+	}
+	return m
+}
+
+// Purge removes all entries from the LFU without calling CacheOptions.OnEvict.
+func (c *LFU[K, V]) Purge() {
+	c.entries = make(map[K]*cacheEntry[K, V])
+	c.freqs = make(map[int]*entryList[K, V])
+	c.minFreq = 0
+}
@@ -0,0 +1,138 @@
+package structs
+
+import (
+	"fmt"
+	"math"
+)
+
+const countMinSketchMagic = "CMSK"
+
+// CountMinSketch is a space-efficient probabilistic structure that estimates how many times each item has been seen
+// within a stream. Estimates are never less than the true count, and exceed it by at most epsilon * Total with a
+// probability of 1 - delta, where epsilon and delta are given when the CountMinSketch is created.
+//
+// CountMinSketch implements encoding.BinaryMarshaler and encoding.BinaryUnmarshaler so that it can be persisted.
+//
+// CountMinSketch is not safe for concurrent use.
+type CountMinSketch struct {
+	// counters is a depth x width matrix of counters stored in row-major order
+	counters []uint64
+	width    uint64
+	depth    int
+	total    uint64
+}
+
+// NewCountMinSketch creates a new CountMinSketch whose estimates exceed the true counts by at most epsilon * Total
+// with a probability of 1 - delta. NewCountMinSketch panics if epsilon or delta are not between 0 and 1.
+func NewCountMinSketch(epsilon, delta float64) *CountMinSketch {
+	if epsilon <= 0 || epsilon >= 1 || delta <= 0 || delta >= 1 {
+		panic(fmt.Errorf("epsilon and delta must be between 0 and 1, not %v and %v", epsilon, delta))
+	}
+	width := uint64(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return NewCountMinSketchSize(width, depth)
+}
+
+// NewCountMinSketchSize creates a new CountMinSketch with the given number of counters per row, and the given number
+// of rows, each of which uses a different hash function. NewCountMinSketchSize panics if width or depth are less than
+// 1.
+func NewCountMinSketchSize(width uint64, depth int) *CountMinSketch {
+	if width < 1 || depth < 1 {
+		panic(fmt.Errorf("count-min sketch must have a width and depth of at least 1, not %d and %d", width, depth))
+	}
+	return &CountMinSketch{counters: make([]uint64, width*uint64(depth)), width: width, depth: depth}
+}
+
+// Width returns the number of counters in each row of the CountMinSketch.
+func (cms *CountMinSketch) Width() uint64 { return cms.width }
+
+// Depth returns the number of rows, and therefore hash functions, in the CountMinSketch.
+func (cms *CountMinSketch) Depth() int { return cms.depth }
+
+// Total returns the sum of all the counts that have been added to the CountMinSketch.
+func (cms *CountMinSketch) Total() uint64 { return cms.total }
+
+// Add adds the given count to the given data. Counters saturate at math.MaxUint64.
+func (cms *CountMinSketch) Add(data []byte, count uint64) {
+	h1, h2 := hashPair(data)
+	for i := 0; i < cms.depth; i++ {
+		c := &cms.counters[uint64(i)*cms.width+location(h1, h2, i, cms.width)]
+		if *c > math.MaxUint64-count {
+			*c = math.MaxUint64
+		} else {
+			*c += count
+		}
+	}
+	cms.total += count
+}
+
+// AddString adds the given count to the given string.
+func (cms *CountMinSketch) AddString(s string, count uint64) { cms.Add([]byte(s), count) }
+
+// Estimate returns the estimated count for the given data. The estimate is never less than the true count.
+func (cms *CountMinSketch) Estimate(data []byte) uint64 {
+	h1, h2 := hashPair(data)
+	estimate := uint64(math.MaxUint64)
+	for i := 0; i < cms.depth; i++ {
+		if c := cms.counters[uint64(i)*cms.width+location(h1, h2, i, cms.width)]; c < estimate {
+			estimate = c
+		}
+	}
+	return estimate
+}
+
+// EstimateString returns the estimated count for the given string.
+func (cms *CountMinSketch) EstimateString(s string) uint64 { return cms.Estimate([]byte(s)) }
+
+// Merge adds all the counts within the other CountMinSketch to the CountMinSketch. Both CountMinSketch(es) must have
+// the same width and depth.
+func (cms *CountMinSketch) Merge(other *CountMinSketch) error {
+	if cms.width != other.width || cms.depth != other.depth {
+		return fmt.Errorf(
+			"cannot merge count-min sketches of different sizes (%d, %d) and (%d, %d)",
+			cms.width, cms.depth, other.width, other.depth,
+		)
+	}
+	for i, c := range other.counters {
+		if cms.counters[i] > math.MaxUint64-c {
+			cms.counters[i] = math.MaxUint64
+		} else {
+			cms.counters[i] += c
+		}
+	}
+	cms.total += other.total
+	return nil
+}
+
+// Clear resets all the counts within the CountMinSketch.
+func (cms *CountMinSketch) Clear() {
+	for i := range cms.counters {
+		cms.counters[i] = 0
+	}
+	cms.total = 0
+}
+
+// MarshalBinary encodes the CountMinSketch into a binary form.
+func (cms *CountMinSketch) MarshalBinary() ([]byte, error) {
+	header := binaryHeader{M: cms.width, K: uint64(cms.depth), N: cms.total}
+	copy(header.Magic[:], countMinSketchMagic)
+	return marshalBinary(header, cms.counters)
+}
+
+// UnmarshalBinary decodes a CountMinSketch from the binary form produced by MarshalBinary.
+func (cms *CountMinSketch) UnmarshalBinary(data []byte) error {
+	var counters []uint64
+	header, err := unmarshalBinary(data, countMinSketchMagic, func(header binaryHeader) (any, error) {
+		if header.M < 1 || header.K < 1 || header.M > uint64(len(data)) || header.K > uint64(len(data)) ||
+			header.M*header.K > uint64(len(data)) {
+			return nil, fmt.Errorf("invalid count-min sketch size (%d, %d)", header.M, header.K)
+		}
+		counters = make([]uint64, header.M*header.K)
+		return counters, nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal CountMinSketch: %w", err)
+	}
+	*cms = CountMinSketch{counters: counters, width: header.M, depth: int(header.K), total: header.N}
+	return nil
+}
@@ -0,0 +1,136 @@
+package structs
+
+const defaultDequeCapacity = 16
+
+// Deque is a double-ended queue that grows and shrinks as needed. It is backed by a RingBuffer that doubles in capacity
+// when it is full, and halves in capacity when it is only a quarter full, so pushing and popping from either end are
+// amortised O(1) and the memory held by a Deque is released once a backlog has cleared. The zero value is an empty
+// Deque that is ready to use.
+//
+// Deque is not safe for concurrent use.
+type Deque[T any] struct {
+	ring *RingBuffer[T]
+	min  int
+}
+
+// NewDeque creates a new, empty, Deque. The Deque never shrinks below the given minimum capacity. If minCapacity <= 0
+// then a default of 16 is used.
+func NewDeque[T any](minCapacity int) *Deque[T] {
+	return new(Deque[T]).lazyInit(minCapacity)
+}
+
+func (d *Deque[T]) lazyInit(minCapacity int) *Deque[T] {
+	if d.ring == nil {
+		if minCapacity <= 0 {
+			minCapacity = defaultDequeCapacity
+		}
+		d.min = minCapacity
+		d.ring = NewRingBuffer[T](minCapacity, false)
+	}
+	return d
+}
+
+// grow doubles the capacity of the Deque if it is full.
+func (d *Deque[T]) grow() {
+	d.lazyInit(0)
+	if d.ring.Full() {
+		d.ring.Resize(d.ring.Cap() * 2)
+	}
+}
+
+// shrink halves the capacity of the Deque if it is only a quarter full.
+func (d *Deque[T]) shrink() {
+	if d.ring.Cap() > d.min && d.ring.Len() <= d.ring.Cap()/4 {
+		capacity := d.ring.Cap() / 2
+		if capacity < d.min {
+			capacity = d.min
+		}
+		d.ring.Resize(capacity)
+	}
+}
+
+// Len returns the number of values in the Deque.
+func (d *Deque[T]) Len() int {
+	if d.ring == nil {
+		return 0
+	}
+	return d.ring.Len()
+}
+
+// Cap returns the current capacity of the Deque.
+func (d *Deque[T]) Cap() int {
+	if d.ring == nil {
+		return 0
+	}
+	return d.ring.Cap()
+}
+
+// PushBack pushes the given value onto the back of the Deque.
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	_ = d.ring.PushBack(v)
+}
+
+// PushFront pushes the given value onto the front of the Deque.
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	_ = d.ring.PushFront(v)
+}
+
+// PopFront removes and returns the value at the front of the Deque. ok is false if the Deque is empty.
+func (d *Deque[T]) PopFront() (v T, ok bool) {
+	if d.Len() == 0 {
+		return
+	}
+	v, ok = d.ring.PopFront()
+	d.shrink()
+	return
+}
+
+// PopBack removes and returns the value at the back of the Deque. ok is false if the Deque is empty.
+func (d *Deque[T]) PopBack() (v T, ok bool) {
+	if d.Len() == 0 {
+		return
+	}
+	v, ok = d.ring.PopBack()
+	d.shrink()
+	return
+}
+
+// Front returns the value at the front of the Deque. ok is false if the Deque is empty.
+func (d *Deque[T]) Front() (v T, ok bool) {
+	if d.Len() == 0 {
+		return
+	}
+	return d.ring.Front()
+}
+
+// Back returns the value at the back of the Deque. ok is false if the Deque is empty.
+func (d *Deque[T]) Back() (v T, ok bool) {
+	if d.Len() == 0 {
+		return
+	}
+	return d.ring.Back()
+}
+
+// At returns the value at the given index, where index 0 is the front of the Deque. At panics if the index is out of
+// range.
+func (d *Deque[T]) At(i int) T {
+	d.lazyInit(0)
+	return d.ring.At(i)
+}
+
+// Clear removes every value from the Deque and shrinks it back to its minimum capacity.
+func (d *Deque[T]) Clear() {
+	if d.ring != nil {
+		d.ring = NewRingBuffer[T](d.min, false)
+	}
+}
+
+// Values returns the values within the Deque from front to back.
+func (d *Deque[T]) Values() []T {
+	if d.ring == nil {
+		return []T{}
+	}
+	return d.ring.Values()
+}
@@ -0,0 +1,125 @@
+package structs
+
+// DisjointSet is a union-find structure that partitions elements into disjoint sets. It uses path compression and
+// union by rank, so Find and Union run in amortised near-constant time. Elements are remembered in the order that they
+// were added so that DisjointSet.Sets is deterministic. The zero value is an empty DisjointSet that is ready to use.
+//
+// DisjointSet is not safe for concurrent use.
+type DisjointSet[T comparable] struct {
+	parent   map[T]T
+	rank     map[T]int
+	size     map[T]int
+	elements []T
+	sets     int
+}
+
+// NewDisjointSet creates a new DisjointSet in which each of the given elements are in a set of their own.
+func NewDisjointSet[T comparable](elements ...T) *DisjointSet[T] {
+	ds := &DisjointSet[T]{}
+	ds.Add(elements...)
+	return ds
+}
+
+// Add adds each of the given elements to a set of their own. Elements that already exist are ignored.
+func (ds *DisjointSet[T]) Add(elements ...T) {
+	if ds.parent == nil {
+		ds.parent = make(map[T]T)
+		ds.rank = make(map[T]int)
+		ds.size = make(map[T]int)
+	}
+	for _, element := range elements {
+		if _, ok := ds.parent[element]; !ok {
+			ds.parent[element] = element
+			ds.size[element] = 1
+			ds.elements = append(ds.elements, element)
+			ds.sets++
+		}
+	}
+}
+
+// Contains returns whether the given element has been added to the DisjointSet.
+func (ds *DisjointSet[T]) Contains(element T) bool {
+	_, ok := ds.parent[element]
+	return ok
+}
+
+// Len returns the number of elements within the DisjointSet.
+func (ds *DisjointSet[T]) Len() int { return len(ds.elements) }
+
+// Count returns the number of disjoint sets.
+func (ds *DisjointSet[T]) Count() int { return ds.sets }
+
+// Find returns the representative element of the set that contains the given element. Elements that have not been
+// added are added to a set of their own.
+func (ds *DisjointSet[T]) Find(element T) T {
+	ds.Add(element)
+	root := element
+	for ds.parent[root] != root {
+		root = ds.parent[root]
+	}
+
+	// Compress the path so that every element along it points directly to the root
+	for element != root {
+		next := ds.parent[element]
+		ds.parent[element] = root
+		element = next
+	}
+	return root
+}
+
+// Union merges the sets that contain the two given elements, adding the elements if they have not been added. Returns
+// false if the elements were already in the same set.
+func (ds *DisjointSet[T]) Union(a, b T) bool {
+	rootA, rootB := ds.Find(a), ds.Find(b)
+	if rootA == rootB {
+		return false
+	}
+
+	if ds.rank[rootA] < ds.rank[rootB] {
+		rootA, rootB = rootB, rootA
+	} else if ds.rank[rootA] == ds.rank[rootB] {
+		ds.rank[rootA]++
+	}
+	ds.parent[rootB] = rootA
+	ds.size[rootA] += ds.size[rootB]
+	delete(ds.size, rootB)
+	delete(ds.rank, rootB)
+	ds.sets--
+	return true
+}
+
+// Connected returns whether the two given elements are in the same set. Elements that have not been added are never
+// connected to anything other than themselves.
+func (ds *DisjointSet[T]) Connected(a, b T) bool {
+	if !ds.Contains(a) || !ds.Contains(b) {
+		return a == b
+	}
+	return ds.Find(a) == ds.Find(b)
+}
+
+// Size returns the number of elements within the set that contains the given element. Returns 0 if the element has not
+// been added.
+func (ds *DisjointSet[T]) Size(element T) int {
+	if !ds.Contains(element) {
+		return 0
+	}
+	return ds.size[ds.Find(element)]
+}
+
+// Sets returns each of the disjoint sets. Sets are ordered by the first of their elements to be added, and the elements
+// within each set are in the order that they were added.
+func (ds *DisjointSet[T]) Sets() [][]T {
+	sets := make([][]T, 0, ds.sets)
+	index := make(map[T]int, ds.sets)
+	for _, element := range ds.elements {
+		root := ds.Find(element)
+		i, ok := index[root]
+		if !ok {
+			i = len(sets)
+			index[root] = i
+			sets = append(sets, nil)
+		}
+		sets[i] = append(sets[i], element)
+	}
+	return sets
+}
@@ -2,7 +2,14 @@ package structs
 
 import (
 	"container/heap"
+	"encoding/json"
 	"fmt"
+	"github.com/andygello555/gotils/v2/files"
+	"github.com/andygello555/gotils/v2/misc"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 // How to create and use a Heap.
@@ -44,3 +51,546 @@ func ExampleHeap() {
 	// Orange
 	// Length after: 0
 }
+
+// Create an LRU with a capacity of 3 and see which entries are evicted.
+func ExampleLRU() {
+	cache := NewLRU[string, int](3, CacheOptions[string, int]{
+		OnEvict: func(key string, val int, reason EvictReason) {
+			fmt.Printf("Evicted %s=%d (%s)\n", key, val, reason)
+		},
+	})
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	// Getting "a" makes it the most recently used entry, so "b" is evicted instead
+	cache.Get("a")
+	cache.Set("d", 4)
+	fmt.Println(cache.Keys())
+	// Output:
+	// Evicted b=2 (Capacity)
+	// [d a c]
+}
+
+// Create an LRU whose entries expire after a minute. The current time is faked using CacheOptions.Now.
+func ExampleLRU_SetWithTTL() {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewLRU[string, int](0, CacheOptions[string, int]{
+		TTL: time.Minute,
+		Now: func() time.Time { return now },
+	})
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, time.Hour)
+	cache.SetWithTTL("c", 3, 0)
+
+	now = now.Add(time.Minute)
+	_, ok := cache.Get("a")
+	fmt.Println("a exists:", ok)
+	fmt.Println(cache.ToMap())
+	// Output:
+	// a exists: false
+	// map[b:2 c:3]
+}
+
+// Create an LFU with a capacity of 2 and see which entries are evicted.
+func ExampleLFU() {
+	cache := NewLFU[string, int](2, CacheOptions[string, int]{
+		OnEvict: func(key string, val int, reason EvictReason) {
+			fmt.Printf("Evicted %s=%d (%s)\n", key, val, reason)
+		},
+	})
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("b")
+
+	// "b" has been used less than "a", so it is evicted
+	cache.Set("c", 3)
+	fmt.Println(cache.Keys())
+	fmt.Println("Frequency of a:", cache.Frequency("a"))
+	// Output:
+	// Evicted b=2 (Capacity)
+	// [a c]
+	// Frequency of a: 3
+}
+
+// Create a PriorityQueue of tasks that are ordered by their priority, and use the returned handles to change a task's
+// priority, and to remove a task.
+func ExamplePriorityQueue() {
+	type task struct {
+		name     string
+		priority int
+	}
+	tasks := NewMaxPriorityQueue(func(a, b task) bool { return a.priority < b.priority })
+	tasks.Push(task{"Write tests", 2})
+	laundry := tasks.Push(task{"Do laundry", 1})
+	emails := tasks.Push(task{"Reply to emails", 3})
+	tasks.Push(task{"Fix bug", 3})
+
+	// Update the priority of the laundry, and remove the emails
+	tasks.Update(laundry, task{"Do laundry", 4})
+	tasks.Remove(emails)
+
+	next, _ := tasks.Peek()
+	fmt.Println("Next:", next.name)
+	for _, t := range tasks.Drain() {
+		fmt.Println(t.priority, t.name)
+	}
+	fmt.Println("Emails queued:", emails.Queued())
+	// Output:
+	// Next: Do laundry
+	// 4 Do laundry
+	// 3 Fix bug
+	// 2 Write tests
+	// Emails queued: false
+}
+
+// Create a PriorityQueue of constraints.Ordered values.
+func ExampleNewOrderedPriorityQueue() {
+	pq := NewOrderedPriorityQueue[string](false)
+	for _, s := range []string{"Crisps", "Egg", "Bananas", "Apple"} {
+		pq.Push(s)
+	}
+	for pq.Len() > 0 {
+		s, _ := pq.Pop()
+		fmt.Println(s)
+	}
+	// Output:
+	// Apple
+	// Bananas
+	// Crisps
+	// Egg
+}
+
+// Perform some set algebra on two Sets.
+func ExampleSet() {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+
+	fmt.Println(a.Contains(1), b.Contains(1))
+	fmt.Println(a.UnionNew(b).Len())
+	fmt.Println(a.IntersectionNew(b).Equal(NewSet(3, 4)))
+	fmt.Println(a.DifferenceNew(b).Equal(NewSet(1, 2)))
+	fmt.Println(a.SymmetricDifferenceNew(b).Equal(NewSet(1, 2, 5)))
+	fmt.Println(NewSet(3, 4).IsSubset(a), b.IsSubset(a))
+
+	// Modify a in-place
+	a.Difference(b)
+	a.Add(6)
+	a.Remove(1)
+	fmt.Println(a.Equal(NewSet(2, 6)))
+	// Output:
+	// true false
+	// 5
+	// true
+	// true
+	// true
+	// true false
+	// true
+}
+
+// Sets are marshalled to, and unmarshalled from, JSON arrays.
+func ExampleSet_MarshalJSON() {
+	s := NewSet("world", "hello", "!")
+	data, _ := json.Marshal(s)
+	fmt.Println(string(data))
+
+	var t Set[string]
+	_ = json.Unmarshal([]byte(`["a", "b", "a"]`), &t)
+	fmt.Println(t.Len(), t.Contains("a"), t.Contains("b"))
+	// Output:
+	// ["!","hello","world"]
+	// 2 true true
+}
+
+// Create an OrderedMap and move some of its keys around.
+func ExampleOrderedMap() {
+	om := NewOrderedMap[string, int]()
+	om.Set("b", 2)
+	om.Set("a", 1)
+	om.Set("c", 3)
+	om.Set("b", 20)
+	fmt.Println(om.Keys(), om.Values())
+
+	om.MoveToFront("c")
+	om.MoveToBack("b")
+	om.Delete("a")
+	om.Range(func(i int, key string, val int) bool {
+		fmt.Println(i, key, val)
+		return true
+	})
+	// Output:
+	// [b a c] [20 1 3]
+	// 0 c 3
+	// 1 b 20
+}
+
+// Round-trip a JSON object through an OrderedMap without losing the order of its keys.
+func ExampleOrderedMap_UnmarshalJSON() {
+	var om OrderedMap[string, any]
+	_ = json.Unmarshal([]byte(`{"zebra": 1, "apple": {"nested": true}, "mango": [1, 2]}`), &om)
+	fmt.Println(om.Keys())
+
+	apple, _ := om.Get("apple")
+	fmt.Printf("%T\n", apple)
+
+	data, _ := json.Marshal(&om)
+	fmt.Println(string(data))
+	// Output:
+	// [zebra apple mango]
+	// map[string]interface {}
+	// {"zebra":1,"apple":{"nested":true},"mango":[1,2]}
+}
+
+// Use a Deque as both a stack and a queue.
+func ExampleDeque() {
+	var d Deque[int]
+	for i := 1; i <= 3; i++ {
+		d.PushBack(i)
+	}
+	d.PushFront(0)
+	fmt.Println(d.Values())
+
+	front, _ := d.PopFront()
+	back, _ := d.PopBack()
+	fmt.Println(front, back, d.Values())
+	// Output:
+	// [0 1 2 3]
+	// 0 3 [1 2]
+}
+
+// Keep only the last 3 values that were pushed onto a RingBuffer by using overwrite mode.
+func ExampleRingBuffer() {
+	last := NewRingBuffer[string](3, true)
+	for _, s := range []string{"a", "b", "c", "d", "e"} {
+		_ = last.PushBack(s)
+	}
+	fmt.Println(last.Values())
+
+	// Without overwrite mode, pushing onto a full RingBuffer returns an error
+	rb := NewRingBuffer[string](1, false)
+	fmt.Println(rb.PushBack("a"))
+	fmt.Println(rb.PushBack("b"))
+
+	// Resizing keeps the existing values
+	rb.Resize(2)
+	fmt.Println(rb.PushBack("b"), rb.Values())
+	// Output:
+	// [c d e]
+	// <nil>
+	// ring buffer is full
+	// <nil> [a b]
+}
+
+// Create a List and move its Element(s) around.
+func ExampleList() {
+	l := NewList(1, 2, 3)
+	four := l.PushBack(4)
+	l.MoveToFront(four)
+	l.InsertAfter(5, four)
+	l.Remove(l.Back())
+	fmt.Println(l.Values())
+
+	for e := l.Back(); e != nil; e = e.Prev() {
+		fmt.Print(e.Value, " ")
+	}
+	fmt.Println()
+	// Output:
+	// [4 5 1 2]
+	// 2 1 5 4
+}
+
+// Create a TreeMap and perform some ordered queries on it.
+func ExampleTreeMap() {
+	tm := NewTreeMap[int, string]()
+	for _, key := range []int{50, 10, 40, 20, 30} {
+		tm.Set(key, fmt.Sprintf("#%d", key))
+	}
+	fmt.Println(tm.Keys())
+
+	floor, _, _ := tm.Floor(35)
+	ceiling, _, _ := tm.Ceiling(35)
+	fmt.Println("Floor:", floor, "Ceiling:", ceiling)
+
+	minKey, _, _ := tm.Min()
+	maxKey, _, _ := tm.Max()
+	fmt.Println("Min:", minKey, "Max:", maxKey)
+
+	second, _, _ := tm.Select(1)
+	fmt.Println("Rank of 40:", tm.Rank(40), "Select(1):", second)
+
+	tm.Range(15, 40, func(i int, key int, val string) bool {
+		fmt.Println(i, key, val)
+		return true
+	})
+	// Output:
+	// [10 20 30 40 50]
+	// Floor: 30 Ceiling: 40
+	// Min: 10 Max: 50
+	// Rank of 40: 3 Select(1): 20
+	// 0 20 #20
+	// 1 30 #30
+	// 2 40 #40
+}
+
+// Create a TreeMap whose keys are ordered using a custom comparison function.
+func ExampleNewTreeMapFunc() {
+	tm := NewTreeMapFunc[string, int](func(a, b string) misc.Ordered {
+		return misc.Compare(len(a), len(b))
+	})
+	tm.Set("ccc", 3)
+	tm.Set("a", 1)
+	tm.Set("bb", 2)
+	tm.Descend(func(i int, key string, val int) bool {
+		fmt.Println(key, val)
+		return true
+	})
+	// Output:
+	// ccc 3
+	// bb 2
+	// a 1
+}
+
+// Create a Trie and perform some prefix queries on it.
+func ExampleTrie() {
+	routes := NewTrie[string]()
+	routes.Set("/", "index")
+	routes.Set("/users", "list users")
+	routes.Set("/users/me", "current user")
+	routes.Set("/uploads", "list uploads")
+	routes.Set("/café", "menu")
+
+	fmt.Println(routes.KeysWithPrefix("/u"))
+	prefix, handler, _ := routes.LongestPrefix("/users/123")
+	fmt.Println(prefix, "->", handler)
+
+	routes.Walk("/users/me/settings", func(prefix string, handler string) bool {
+		fmt.Println(prefix, handler)
+		return true
+	})
+
+	routes.Delete("/users")
+	fmt.Println(routes.Len(), routes.HasPrefix("/users"), routes.Contains("/users"))
+	fmt.Println(routes.HasPrefix("/caf"), routes.HasPrefix("/cafe"))
+	// Output:
+	// [/uploads /users /users/me]
+	// /users -> list users
+	// / index
+	// /users list users
+	// /users/me current user
+	// 4 true false
+	// true false
+}
+
+// De-duplicate a stream of strings using a BloomFilter, then persist it to a file and load it back again.
+func ExampleBloomFilter() {
+	seen := NewBloomFilter(1000, 0.01)
+	for _, s := range []string{"apple", "banana", "apple", "cherry", "banana"} {
+		if !seen.ContainsString(s) {
+			seen.AddString(s)
+			fmt.Println("New:", s)
+		}
+	}
+
+	// Persist the BloomFilter to a temporary file
+	dir, _ := os.MkdirTemp("", "bloom")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "seen.bloom")
+	data, _ := seen.MarshalBinary()
+	_ = os.WriteFile(path, data, 0o644)
+	fmt.Println("Persisted:", files.Exists(path))
+
+	// Load it back again
+	loaded := &BloomFilter{}
+	data, _ = os.ReadFile(path)
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		fmt.Println(err)
+	}
+	fmt.Println(loaded.ContainsString("cherry"), loaded.ContainsString("durian"), loaded.Count())
+	// Output:
+	// New: apple
+	// New: banana
+	// New: cherry
+	// Persisted: true
+	// true false 3
+}
+
+// Use a CountingBloomFilter to track which sessions are currently active.
+func ExampleCountingBloomFilter() {
+	active := NewCountingBloomFilter(100, 0.01)
+	active.AddString("session-1")
+	active.AddString("session-2")
+	fmt.Println(active.ContainsString("session-1"), active.Count())
+
+	fmt.Println(active.RemoveString("session-1"), active.RemoveString("session-3"))
+	fmt.Println(active.ContainsString("session-1"), active.ContainsString("session-2"), active.Count())
+	// Output:
+	// true 2
+	// true false
+	// false true 1
+}
+
+// Estimate the frequency of words using a CountMinSketch.
+func ExampleCountMinSketch() {
+	sketch := NewCountMinSketch(0.001, 0.01)
+	for _, word := range strings.Fields("the cat sat on the mat and the dog sat too") {
+		sketch.AddString(word, 1)
+	}
+	fmt.Println(sketch.EstimateString("the"), sketch.EstimateString("sat"), sketch.EstimateString("bird"))
+	fmt.Println(sketch.Total())
+	// Output:
+	// 3 2 0
+	// 11
+}
+
+// Resolve the order in which some packages should be built using a topological sort.
+func ExampleGraph_TopologicalSort() {
+	deps := NewGraph[string, int](true)
+	deps.AddEdge("misc", "slices", 1)
+	deps.AddEdge("numbers", "slices", 1)
+	deps.AddEdge("slices", "strings", 1)
+	deps.AddEdge("slices", "structs", 1)
+	deps.AddEdge("misc", "structs", 1)
+	order, _ := deps.TopologicalSort()
+	fmt.Println(order)
+
+	// Introduce a cycle
+	deps.AddEdge("structs", "misc", 1)
+	_, err := deps.TopologicalSort()
+	fmt.Println(err)
+	// Output:
+	// [misc numbers slices strings structs]
+	// graph contains a cycle: misc -> slices -> structs -> misc
+}
+
+// Find the shortest path between two nodes in a weighted, undirected, Graph.
+func ExampleGraph_ShortestPath() {
+	roads := NewGraph[string, float64](false)
+	roads.AddEdge("A", "B", 4)
+	roads.AddEdge("A", "C", 1)
+	roads.AddEdge("C", "B", 2)
+	roads.AddEdge("B", "D", 5)
+	roads.AddEdge("C", "D", 8)
+	roads.AddNode("E")
+
+	path, dist, _ := roads.ShortestPath("A", "D")
+	fmt.Println(path, dist)
+	_, _, err := roads.ShortestPath("A", "E")
+	fmt.Println(err)
+	// Output:
+	// [A C B D] 8
+	// no path between nodes
+}
+
+// Traverse a Graph, find its components, and export it to the DOT language.
+func ExampleGraph() {
+	g := NewGraph[int, int](true)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(3, 1, 1)
+	g.AddEdge(3, 4, 1)
+	g.AddEdge(5, 6, 1)
+
+	visits := make([]string, 0)
+	_ = g.BFS(1, func(node int, depth int) bool {
+		visits = append(visits, fmt.Sprintf("%d@%d", node, depth))
+		return true
+	})
+	fmt.Println(visits)
+	fmt.Println(g.ConnectedComponents())
+	fmt.Println(g.StronglyConnectedComponents())
+
+	g.RemoveNode(3)
+	fmt.Print(g.DOT("example"))
+	// Output:
+	// [1@0 2@1 3@2 4@3]
+	// [[1 2 3 4] [5 6]]
+	// [[4] [1 2 3] [6] [5]]
+	// digraph "example" {
+	// 	"1";
+	// 	"2";
+	// 	"4";
+	// 	"5";
+	// 	"6";
+	// 	"1" -> "2" [label="1"];
+	// 	"5" -> "6" [label="1"];
+	// }
+}
+
+// Group people into friendship circles using a DisjointSet.
+func ExampleDisjointSet() {
+	friends := NewDisjointSet("alice", "bob", "carol", "dave", "erin")
+	friends.Union("alice", "bob")
+	friends.Union("dave", "erin")
+	friends.Union("bob", "carol")
+	fmt.Println(friends.Connected("alice", "carol"), friends.Connected("alice", "dave"))
+	fmt.Println(friends.Count(), friends.Size("carol"))
+	fmt.Println(friends.Sets())
+	// Output:
+	// true false
+	// 2 3
+	// [[alice bob carol] [dave erin]]
+}
+
+// Find which meetings are happening at a given time, and which clash with a new meeting.
+func ExampleIntervalTree() {
+	meetings := NewIntervalTree[int, string]()
+	meetings.Insert(900, 1000, "stand-up")
+	meetings.Insert(930, 1130, "planning")
+	meetings.Insert(1300, 1400, "lunch")
+	meetings.Insert(1500, 1600, "retro")
+
+	for _, meeting := range meetings.Stab(945) {
+		fmt.Println(meeting.Value)
+	}
+	clashes := make([]string, 0)
+	for _, meeting := range meetings.Overlapping(1100, 1330) {
+		clashes = append(clashes, meeting.Value)
+	}
+	fmt.Println(clashes)
+
+	meetings.Delete(1300, 1400)
+	fmt.Println(meetings.Overlaps(1200, 1430), meetings.Len())
+	// Output:
+	// stand-up
+	// planning
+	// [planning lunch]
+	// false 3
+}
+
+// Take cheap snapshots of a PersistentVector. Updating a PersistentVector never affects older versions.
+func ExamplePersistentVector() {
+	v1 := NewPersistentVector(1, 2, 3)
+	v2 := v1.Append(4, 5)
+	v3 := v2.Set(0, 100)
+	v4, last, _ := v3.Pop()
+	fmt.Println(v1.Values(), v2.Values(), v3.Values(), v4.Values())
+	fmt.Println(last, v3.At(0), v4.Len())
+	// Output:
+	// [1 2 3] [1 2 3 4 5] [100 2 3 4 5] [100 2 3 4]
+	// 5 100 4
+}
+
+// Share a snapshot of some configuration with a goroutine whilst continuing to update it.
+func ExamplePersistentMap() {
+	config := NewPersistentMapFrom(map[string]int{"workers": 4, "retries": 3})
+	snapshot := config
+
+	read := make(chan int)
+	go func() {
+		workers, _ := snapshot.Get("workers")
+		read <- workers
+	}()
+
+	// The snapshot can be read whilst config is being updated
+	config = config.Set("workers", 8).Delete("retries")
+	fmt.Println("snapshot workers:", <-read)
+	workers, _ := config.Get("workers")
+	fmt.Println("config workers:", workers, config.Contains("retries"))
+	fmt.Println(snapshot.Len(), config.Len())
+	// Output:
+	// snapshot workers: 4
+	// config workers: 8 false
+	// 2 1
+}
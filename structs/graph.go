@@ -0,0 +1,552 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"github.com/andygello555/gotils/v2/numbers"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNodeNotFound is returned when a Graph is queried for a node that it does not contain.
+	ErrNodeNotFound = errors.New("node not found")
+	// ErrNoPath is returned by Graph.ShortestPath when there is no path between the two nodes.
+	ErrNoPath = errors.New("no path between nodes")
+	// ErrNegativeWeight is returned by Graph.Dijkstra when it encounters an edge with a negative weight.
+	ErrNegativeWeight = errors.New("edge has a negative weight")
+	// ErrUndirected is returned by Graph.TopologicalSort when called on an undirected Graph.
+	ErrUndirected = errors.New("graph is undirected")
+)
+
+// CycleError is returned by Graph.TopologicalSort when the Graph contains a cycle.
+type CycleError[N comparable] struct {
+	// Cycle is the path of the cycle that was found. The first and last nodes are the same.
+	Cycle []N
+}
+
+func (ce *CycleError[N]) Error() string {
+	nodes := make([]string, len(ce.Cycle))
+	for i, node := range ce.Cycle {
+		nodes[i] = fmt.Sprint(node)
+	}
+	return "graph contains a cycle: " + strings.Join(nodes, " -> ")
+}
+
+// Edge is a weighted edge between two nodes within a Graph.
+type Edge[N comparable, W numbers.Number] struct {
+	From   N
+	To     N
+	Weight W
+}
+
+// graphNode is a node within a Graph. Its outgoing edges are kept in insertion order.
+type graphNode[N comparable, W numbers.Number] struct {
+	id      N
+	edges   []N
+	weights map[N]W
+}
+
+func (n *graphNode[N, W]) setEdge(to N, weight W) bool {
+	if _, ok := n.weights[to]; ok {
+		n.weights[to] = weight
+		return false
+	}
+	n.edges = append(n.edges, to)
+	n.weights[to] = weight
+	return true
+}
+
+func (n *graphNode[N, W]) removeEdge(to N) bool {
+	if _, ok := n.weights[to]; !ok {
+		return false
+	}
+	delete(n.weights, to)
+	for i, edge := range n.edges {
+		if edge == to {
+			n.edges = append(n.edges[:i], n.edges[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Graph is a directed or undirected graph whose edges are weighted using a numbers.Number. Nodes and edges are kept
+// in insertion order, so every traversal and algorithm on a Graph is deterministic.
+//
+// Graph is not safe for concurrent use.
+type Graph[N comparable, W numbers.Number] struct {
+	directed bool
+	nodes    []*graphNode[N, W]
+	lookup   map[N]*graphNode[N, W]
+	edges    int
+}
+
+// NewGraph creates a new, empty, Graph which is either directed or undirected.
+func NewGraph[N comparable, W numbers.Number](directed bool) *Graph[N, W] {
+	return &Graph[N, W]{directed: directed, lookup: make(map[N]*graphNode[N, W])}
+}
+
+// Directed returns whether the Graph is directed.
+func (g *Graph[N, W]) Directed() bool { return g.directed }
+
+// NodeCount returns the number of nodes within the Graph.
+func (g *Graph[N, W]) NodeCount() int { return len(g.nodes) }
+
+// EdgeCount returns the number of edges within the Graph. Each edge of an undirected Graph is only counted once.
+func (g *Graph[N, W]) EdgeCount() int { return g.edges }
+
+// AddNode adds the given nodes to the Graph. Nodes that already exist are ignored.
+func (g *Graph[N, W]) AddNode(nodes ...N) {
+	for _, node := range nodes {
+		g.node(node)
+	}
+}
+
+// node returns the graphNode for the given node, adding it if it does not exist.
+func (g *Graph[N, W]) node(id N) *graphNode[N, W] {
+	n, ok := g.lookup[id]
+	if !ok {
+		n = &graphNode[N, W]{id: id, weights: make(map[N]W)}
+		g.lookup[id] = n
+		g.nodes = append(g.nodes, n)
+	}
+	return n
+}
+
+// HasNode returns whether the given node exists.
+func (g *Graph[N, W]) HasNode(node N) bool {
+	_, ok := g.lookup[node]
+	return ok
+}
+
+// RemoveNode removes the given node and all of its edges. Returns whether the node existed.
+func (g *Graph[N, W]) RemoveNode(node N) bool {
+	n, ok := g.lookup[node]
+	if !ok {
+		return false
+	}
+	for _, other := range g.nodes {
+		if other != n && other.removeEdge(node) && g.directed {
+			g.edges--
+		}
+	}
+	g.edges -= len(n.edges)
+	delete(g.lookup, node)
+	for i, other := range g.nodes {
+		if other == n {
+			g.nodes = append(g.nodes[:i], g.nodes[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// AddEdge adds an edge with the given weight between the given nodes, adding the nodes if they do not exist. If the
+// Graph is undirected then the edge can be traversed in both directions. If the edge already exists then its weight is
+// updated.
+func (g *Graph[N, W]) AddEdge(from, to N, weight W) {
+	added := g.node(from).setEdge(to, weight)
+	toNode := g.node(to)
+	if !g.directed {
+		toNode.setEdge(from, weight)
+	}
+	if added {
+		g.edges++
+	}
+}
+
+// RemoveEdge removes the edge between the given nodes. Returns whether the edge existed.
+func (g *Graph[N, W]) RemoveEdge(from, to N) bool {
+	n, ok := g.lookup[from]
+	if !ok || !n.removeEdge(to) {
+		return false
+	}
+	if !g.directed {
+		g.lookup[to].removeEdge(from)
+	}
+	g.edges--
+	return true
+}
+
+// HasEdge returns whether there is an edge between the given nodes.
+func (g *Graph[N, W]) HasEdge(from, to N) bool {
+	_, ok := g.Weight(from, to)
+	return ok
+}
+
+// Weight returns the weight of the edge between the given nodes, and whether the edge exists.
+func (g *Graph[N, W]) Weight(from, to N) (weight W, ok bool) {
+	if n, exists := g.lookup[from]; exists {
+		weight, ok = n.weights[to]
+	}
+	return
+}
+
+// Nodes returns the nodes within the Graph in the order that they were added.
+func (g *Graph[N, W]) Nodes() []N {
+	nodes := make([]N, len(g.nodes))
+	for i, n := range g.nodes {
+		nodes[i] = n.id
+	}
+	return nodes
+}
+
+// Neighbours returns the nodes that can be reached from the given node by following a single edge, in the order that
+// the edges were added. Returns nil if the node does not exist.
+func (g *Graph[N, W]) Neighbours(node N) []N {
+	n, ok := g.lookup[node]
+	if !ok {
+		return nil
+	}
+	return append([]N{}, n.edges...)
+}
+
+// Edges returns the edges within the Graph. Edges are ordered by their From node, then by the order in which they
+// were added. Each edge of an undirected Graph is only returned once.
+func (g *Graph[N, W]) Edges() []Edge[N, W] {
+	position := make(map[N]int, len(g.nodes))
+	for i, n := range g.nodes {
+		position[n.id] = i
+	}
+
+	edges := make([]Edge[N, W], 0, g.edges)
+	for i, n := range g.nodes {
+		for _, to := range n.edges {
+			if g.directed || position[to] >= i {
+				edges = append(edges, Edge[N, W]{From: n.id, To: to, Weight: n.weights[to]})
+			}
+		}
+	}
+	return edges
+}
+
+// BFS performs a breadth-first traversal of the Graph from the given node. The given function is called on each
+// reachable node along with its depth, i.e. the number of edges between it and the start node. The function should
+// return whether you want to keep traversing. Returns ErrNodeNotFound if the start node does not exist.
+func (g *Graph[N, W]) BFS(start N, fun func(node N, depth int) bool) error {
+	if !g.HasNode(start) {
+		return ErrNodeNotFound
+	}
+
+	type visit struct {
+		node  N
+		depth int
+	}
+	visited := map[N]struct{}{start: {}}
+	queue := NewDeque[visit](0)
+	queue.PushBack(visit{start, 0})
+	for queue.Len() > 0 {
+		v, _ := queue.PopFront()
+		if !fun(v.node, v.depth) {
+			return nil
+		}
+		for _, to := range g.lookup[v.node].edges {
+			if _, ok := visited[to]; !ok {
+				visited[to] = struct{}{}
+				queue.PushBack(visit{to, v.depth + 1})
+			}
+		}
+	}
+	return nil
+}
+
+// DFS performs a depth-first traversal of the Graph from the given node. The given function is called on each
+// reachable node, in pre-order, along with its depth within the traversal. The function should return whether you
+// want to keep traversing. Returns ErrNodeNotFound if the start node does not exist.
+func (g *Graph[N, W]) DFS(start N, fun func(node N, depth int) bool) error {
+	if !g.HasNode(start) {
+		return ErrNodeNotFound
+	}
+
+	type frame struct {
+		node  *graphNode[N, W]
+		depth int
+		next  int
+	}
+	visited := map[N]struct{}{start: {}}
+	stack := []frame{{node: g.lookup[start]}}
+	if !fun(start, 0) {
+		return nil
+	}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == len(top.node.edges) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		to := top.node.edges[top.next]
+		top.next++
+		if _, ok := visited[to]; ok {
+			continue
+		}
+		visited[to] = struct{}{}
+		if !fun(to, top.depth+1) {
+			return nil
+		}
+		stack = append(stack, frame{node: g.lookup[to], depth: top.depth + 1})
+	}
+	return nil
+}
+
+// TopologicalSort returns the nodes of a directed Graph ordered so that every node comes before all the nodes that it
+// has edges to. Nodes that are not ordered relative to each other are returned in the order that they were added.
+//
+// If the Graph contains a cycle then a *CycleError is returned. ErrUndirected is returned for undirected Graph(s).
+func (g *Graph[N, W]) TopologicalSort() ([]N, error) {
+	if !g.directed {
+		return nil, ErrUndirected
+	}
+
+	// Kahn's algorithm, using a PriorityQueue ordered by insertion position so that the output is deterministic
+	position := make(map[N]int, len(g.nodes))
+	inDegree := make(map[N]int, len(g.nodes))
+	for i, n := range g.nodes {
+		position[n.id] = i
+		for _, to := range n.edges {
+			inDegree[to]++
+		}
+	}
+
+	ready := NewPriorityQueue(func(a, b N) bool { return position[a] < position[b] })
+	for _, n := range g.nodes {
+		if inDegree[n.id] == 0 {
+			ready.Push(n.id)
+		}
+	}
+
+	order := make([]N, 0, len(g.nodes))
+	for ready.Len() > 0 {
+		node, _ := ready.Pop()
+		order = append(order, node)
+		for _, to := range g.lookup[node].edges {
+			if inDegree[to]--; inDegree[to] == 0 {
+				ready.Push(to)
+			}
+		}
+	}
+
+	if len(order) < len(g.nodes) {
+		return nil, &CycleError[N]{Cycle: g.findCycle(inDegree)}
+	}
+	return order, nil
+}
+
+// findCycle finds a cycle among the nodes that still have a positive in-degree after Kahn's algorithm.
+func (g *Graph[N, W]) findCycle(inDegree map[N]int) []N {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[N]int)
+	var path []N
+	var visit func(node N) []N
+	visit = func(node N) []N {
+		state[node] = visiting
+		path = append(path, node)
+		for _, to := range g.lookup[node].edges {
+			switch state[to] {
+			case visiting:
+				// The cycle is the part of the path from the first occurrence of to
+				for i, n := range path {
+					if n == to {
+						return append(append([]N{}, path[i:]...), to)
+					}
+				}
+			case unvisited:
+				if cycle := visit(to); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		state[node] = visited
+		path = path[:len(path)-1]
+		return nil
+	}
+
+	for _, n := range g.nodes {
+		if inDegree[n.id] > 0 && state[n.id] == unvisited {
+			if cycle := visit(n.id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// dijkstraEntry is an entry within the PriorityQueue used by Graph.Dijkstra.
+type dijkstraEntry[N comparable, W numbers.Number] struct {
+	node N
+	dist W
+}
+
+// Dijkstra finds the shortest paths from the given node to every node that is reachable from it using Dijkstra's
+// algorithm. It returns the distance to each reachable node, and the previous node on the shortest path to each
+// reachable node other than the source. ErrNegativeWeight is returned if an edge with a negative weight is reachable,
+// and ErrNodeNotFound is returned if the source node does not exist.
+func (g *Graph[N, W]) Dijkstra(source N) (dist map[N]W, prev map[N]N, err error) {
+	if !g.HasNode(source) {
+		return nil, nil, ErrNodeNotFound
+	}
+
+	dist = map[N]W{source: 0}
+	prev = make(map[N]N)
+	done := make(map[N]struct{})
+	pq := NewPriorityQueue(func(a, b dijkstraEntry[N, W]) bool { return a.dist < b.dist })
+	items := map[N]*PriorityQueueItem[dijkstraEntry[N, W]]{source: pq.Push(dijkstraEntry[N, W]{source, 0})}
+
+	for pq.Len() > 0 {
+		entry, _ := pq.Pop()
+		done[entry.node] = struct{}{}
+		n := g.lookup[entry.node]
+		for _, to := range n.edges {
+			weight := n.weights[to]
+			if weight < 0 {
+				return nil, nil, ErrNegativeWeight
+			}
+			if _, ok := done[to]; ok {
+				continue
+			}
+
+			alt := entry.dist + weight
+			if current, ok := dist[to]; !ok || alt < current {
+				dist[to], prev[to] = alt, entry.node
+				if item, queued := items[to]; queued && item.Queued() {
+					pq.Update(item, dijkstraEntry[N, W]{to, alt})
+				} else {
+					items[to] = pq.Push(dijkstraEntry[N, W]{to, alt})
+				}
+			}
+		}
+	}
+	return dist, prev, nil
+}
+
+// ShortestPath returns the shortest path between the given nodes, including both nodes, and its total weight. See
+// Graph.Dijkstra for the errors that can be returned. ErrNoPath is returned if the destination cannot be reached.
+func (g *Graph[N, W]) ShortestPath(from, to N) (path []N, dist W, err error) {
+	if !g.HasNode(to) {
+		return nil, dist, ErrNodeNotFound
+	}
+
+	var distances map[N]W
+	var prev map[N]N
+	if distances, prev, err = g.Dijkstra(from); err != nil {
+		return nil, dist, err
+	}
+	var ok bool
+	if dist, ok = distances[to]; !ok {
+		return nil, dist, ErrNoPath
+	}
+
+	for node := to; ; node = prev[node] {
+		path = append(path, node)
+		if node == from {
+			break
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, dist, nil
+}
+
+// ConnectedComponents returns the connected components of the Graph. For a directed Graph, edge directions are
+// ignored, so the weakly connected components are returned. Components are ordered by their first node, and the nodes
+// within each component are in the order that they were added to the Graph.
+func (g *Graph[N, W]) ConnectedComponents() [][]N {
+	ds := NewDisjointSet[N]()
+	for _, n := range g.nodes {
+		ds.Add(n.id)
+	}
+	for _, n := range g.nodes {
+		for _, to := range n.edges {
+			ds.Union(n.id, to)
+		}
+	}
+	return ds.Sets()
+}
+
+// StronglyConnectedComponents returns the strongly connected components of the Graph using Tarjan's algorithm. Each
+// component is a maximal set of nodes that can all reach each other. Components are returned in reverse topological
+// order, i.e. no component has an edge to a component that comes after it. For an undirected Graph this is the same as
+// ConnectedComponents, apart from the ordering.
+func (g *Graph[N, W]) StronglyConnectedComponents() [][]N {
+	type tarjanState struct {
+		index, lowLink int
+		onStack        bool
+	}
+	states := make(map[N]*tarjanState, len(g.nodes))
+	stack := make([]N, 0)
+	components := make([][]N, 0)
+	index := 0
+
+	var connect func(node N)
+	connect = func(node N) {
+		s := &tarjanState{index: index, lowLink: index, onStack: true}
+		states[node] = s
+		index++
+		stack = append(stack, node)
+
+		for _, to := range g.lookup[node].edges {
+			if toState, ok := states[to]; !ok {
+				connect(to)
+				if low := states[to].lowLink; low < s.lowLink {
+					s.lowLink = low
+				}
+			} else if toState.onStack && toState.index < s.lowLink {
+				s.lowLink = toState.index
+			}
+		}
+
+		if s.lowLink == s.index {
+			component := make([]N, 0)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				states[top].onStack = false
+				component = append(component, top)
+				if top == node {
+					break
+				}
+			}
+			// Reverse the component so that its nodes are in the order that they were visited
+			for i, j := 0, len(component)-1; i < j; i, j = i+1, j-1 {
+				component[i], component[j] = component[j], component[i]
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, n := range g.nodes {
+		if _, ok := states[n.id]; !ok {
+			connect(n.id)
+		}
+	}
+	return components
+}
+
+// DOT returns the Graph in the Graphviz DOT language with the given name. Nodes are labelled using fmt.Sprint and edges
+// are labelled with their weights.
+func (g *Graph[N, W]) DOT(name string) string {
+	var b strings.Builder
+	kind, arrow := "graph", "--"
+	if g.directed {
+		kind, arrow = "digraph", "->"
+	}
+
+	b.WriteString(fmt.Sprintf("%s %s {\n", kind, strconv.Quote(name)))
+	for _, n := range g.nodes {
+		b.WriteString(fmt.Sprintf("\t%s;\n", strconv.Quote(fmt.Sprint(n.id))))
+	}
+	for _, edge := range g.Edges() {
+		b.WriteString(fmt.Sprintf(
+			"\t%s %s %s [label=%s];\n",
+			strconv.Quote(fmt.Sprint(edge.From)), arrow, strconv.Quote(fmt.Sprint(edge.To)),
+			strconv.Quote(fmt.Sprint(edge.Weight)),
+		))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
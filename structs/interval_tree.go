@@ -0,0 +1,192 @@
+package structs
+
+import (
+	"github.com/andygello555/gotils/v2/misc"
+	"github.com/andygello555/gotils/v2/numbers"
+)
+
+// Interval is a closed interval, [Low, High], with an associated value.
+type Interval[N numbers.Number, V any] struct {
+	Low   N
+	High  N
+	Value V
+}
+
+// Overlaps returns whether the Interval overlaps with the closed interval [low, high].
+func (iv Interval[N, V]) Overlaps(low, high N) bool { return iv.Low <= high && low <= iv.High }
+
+// Contains returns whether the given point lies within the Interval.
+func (iv Interval[N, V]) Contains(point N) bool { return iv.Low <= point && point <= iv.High }
+
+// intervalKey orders the intervals within an IntervalTree by Low, then High, then insertion order.
+type intervalKey[N numbers.Number] struct {
+	low, high N
+	seq       uint64
+}
+
+func compareIntervalKeys[N numbers.Number](a, b intervalKey[N]) misc.Ordered {
+	switch {
+	case a.low != b.low:
+		return misc.Compare(a.low, b.low)
+	case a.high != b.high:
+		return misc.Compare(a.high, b.high)
+	}
+	return misc.Compare(a.seq, b.seq)
+}
+
+// intervalEntry is the value of a node within an IntervalTree. max is the greatest High of any interval in the subtree
+// rooted at the node, which allows subtrees that cannot overlap a query to be skipped.
+type intervalEntry[N numbers.Number, V any] struct {
+	val V
+	max N
+}
+
+// nodeInterval returns the Interval stored within the given node of an IntervalTree.
+func nodeInterval[N numbers.Number, V any](n *treeNode[intervalKey[N], intervalEntry[N, V]]) Interval[N, V] {
+	return Interval[N, V]{Low: n.key.low, High: n.key.high, Value: n.val.val}
+}
+
+// IntervalTree stores closed intervals, [Low, High], and finds the intervals that contain a point or overlap another
+// interval. It is a TreeMap ordered by Low, where each node is augmented with the greatest High within its subtree.
+// Insert and Delete are O(log n), and queries are O(log n + m) where m is the number of intervals reported. Intervals
+// with the same bounds can be inserted more than once.
+//
+// IntervalTree is not safe for concurrent use.
+type IntervalTree[N numbers.Number, V any] struct {
+	tree *TreeMap[intervalKey[N], intervalEntry[N, V]]
+	seq  uint64
+}
+
+// NewIntervalTree creates a new, empty, IntervalTree.
+func NewIntervalTree[N numbers.Number, V any]() *IntervalTree[N, V] {
+	tree := NewTreeMapFunc[intervalKey[N], intervalEntry[N, V]](compareIntervalKeys[N])
+	tree.augment = func(n *treeNode[intervalKey[N], intervalEntry[N, V]]) {
+		n.val.max = n.key.high
+		if n.left != nil && n.left.val.max > n.val.max {
+			n.val.max = n.left.val.max
+		}
+		if n.right != nil && n.right.val.max > n.val.max {
+			n.val.max = n.right.val.max
+		}
+	}
+	return &IntervalTree[N, V]{tree: tree}
+}
+
+// Len returns the number of intervals in the IntervalTree.
+func (it *IntervalTree[N, V]) Len() int { return it.tree.Len() }
+
+// Insert adds the closed interval [low, high] with the given value. If low is greater than high then they are
+// swapped.
+func (it *IntervalTree[N, V]) Insert(low, high N, val V) {
+	if low > high {
+		low, high = high, low
+	}
+	it.seq++
+	it.tree.Set(intervalKey[N]{low: low, high: high, seq: it.seq}, intervalEntry[N, V]{val: val})
+}
+
+// Delete removes the interval [low, high]. If the interval was inserted more than once then the earliest insertion is
+// removed. Returns whether the interval existed.
+func (it *IntervalTree[N, V]) Delete(low, high N) bool {
+	if low > high {
+		low, high = high, low
+	}
+
+	// Find the earliest insertion, which is the leftmost node with the given bounds
+	var found *treeNode[intervalKey[N], intervalEntry[N, V]]
+	for n := it.tree.root; n != nil; {
+		switch compareIntervalKeys(intervalKey[N]{low: low, high: high, seq: n.key.seq}, n.key) {
+		case misc.Less:
+			n = n.left
+		case misc.Greater:
+			n = n.right
+		default:
+			found, n = n, n.left
+		}
+	}
+	return found != nil && it.tree.Delete(found.key)
+}
+
+// Overlaps returns whether any interval within the IntervalTree overlaps the closed interval [low, high].
+func (it *IntervalTree[N, V]) Overlaps(low, high N) bool {
+	overlaps := false
+	it.RangeOverlapping(low, high, func(i int, interval Interval[N, V]) bool {
+		overlaps = true
+		return false
+	})
+	return overlaps
+}
+
+// Overlapping returns the intervals that overlap the closed interval [low, high], in ascending order of their bounds.
+func (it *IntervalTree[N, V]) Overlapping(low, high N) []Interval[N, V] {
+	intervals := make([]Interval[N, V], 0)
+	it.RangeOverlapping(low, high, func(i int, interval Interval[N, V]) bool {
+		intervals = append(intervals, interval)
+		return true
+	})
+	return intervals
+}
+
+// Stab returns the intervals that contain the given point, in ascending order of their bounds.
+func (it *IntervalTree[N, V]) Stab(point N) []Interval[N, V] { return it.Overlapping(point, point) }
+
+// RangeOverlapping calls the given function on each index-interval pair that overlaps the closed interval [low, high],
+// in ascending order of their bounds. The function should return whether you want to keep iterating. The IntervalTree
+// must not be modified during iteration.
+func (it *IntervalTree[N, V]) RangeOverlapping(low, high N, fun func(i int, interval Interval[N, V]) bool) {
+	if low > high {
+		low, high = high, low
+	}
+	i := 0
+	it.rangeOverlapping(it.tree.root, low, high, &i, fun)
+}
+
+// rangeOverlapping performs an in-order traversal of the subtree rooted at the given node, skipping any subtrees that
+// cannot contain an overlapping interval. Returns false if iteration should stop.
+func (it *IntervalTree[N, V]) rangeOverlapping(
+	n *treeNode[intervalKey[N], intervalEntry[N, V]],
+	low, high N,
+	i *int,
+	fun func(i int, interval Interval[N, V]) bool,
+) bool {
+	// No interval within the subtree ends at or after low
+	if n == nil || n.val.max < low {
+		return true
+	}
+	if !it.rangeOverlapping(n.left, low, high, i, fun) {
+		return false
+	}
+	// Every interval from here onwards starts after high
+	if n.key.low > high {
+		return true
+	}
+	if iv := nodeInterval(n); iv.Overlaps(low, high) {
+		if !fun(*i, iv) {
+			return false
+		}
+		*i++
+	}
+	return it.rangeOverlapping(n.right, low, high, i, fun)
+}
+
+// Range calls the given function on each index-interval pair within the IntervalTree, in ascending order of their
+// bounds. The function should return whether you want to keep iterating. The IntervalTree must not be modified during
+// iteration.
+func (it *IntervalTree[N, V]) Range(fun func(i int, interval Interval[N, V]) bool) {
+	it.tree.Ascend(func(i int, key intervalKey[N], entry intervalEntry[N, V]) bool {
+		return fun(i, Interval[N, V]{Low: key.low, High: key.high, Value: entry.val})
+	})
+}
+
+// Intervals returns every interval within the IntervalTree, in ascending order of their bounds.
+func (it *IntervalTree[N, V]) Intervals() []Interval[N, V] {
+	intervals := make([]Interval[N, V], 0, it.Len())
+	it.Range(func(i int, interval Interval[N, V]) bool {
+		intervals = append(intervals, interval)
+		return true
+	})
+	return intervals
+}
+
+// Clear removes every interval from the IntervalTree.
+func (it *IntervalTree[N, V]) Clear() { it.tree.Clear() }
@@ -0,0 +1,175 @@
+package structs
+
+// Element is an element within a List.
+type Element[T any] struct {
+	// Value is the value stored within the Element.
+	Value      T
+	prev, next *Element[T]
+	list       *List[T]
+}
+
+// Next returns the next Element in the List, or nil if the Element is the last Element, or has been removed.
+func (e *Element[T]) Next() *Element[T] {
+	if next := e.next; e.list != nil && next != &e.list.root {
+		return next
+	}
+	return nil
+}
+
+// Prev returns the previous Element in the List, or nil if the Element is the first Element, or has been removed.
+func (e *Element[T]) Prev() *Element[T] {
+	if prev := e.prev; e.list != nil && prev != &e.list.root {
+		return prev
+	}
+	return nil
+}
+
+// List is a doubly linked list. It works in the same way as container/list, but stores values of type T rather than
+// any, so values do not need to be type asserted. The zero value is an empty List that is ready to use.
+//
+// List is not safe for concurrent use.
+type List[T any] struct {
+	root   Element[T]
+	length int
+}
+
+// NewList creates a new List containing the given values.
+func NewList[T any](values ...T) *List[T] {
+	l := new(List[T]).lazyInit()
+	for _, v := range values {
+		l.PushBack(v)
+	}
+	return l
+}
+
+func (l *List[T]) lazyInit() *List[T] {
+	if l.root.next == nil {
+		l.root.next, l.root.prev = &l.root, &l.root
+		l.length = 0
+	}
+	return l
+}
+
+// Len returns the number of Element(s) in the List.
+func (l *List[T]) Len() int { return l.length }
+
+// Front returns the first Element of the List, or nil if the List is empty.
+func (l *List[T]) Front() *Element[T] {
+	if l.length == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back returns the last Element of the List, or nil if the List is empty.
+func (l *List[T]) Back() *Element[T] {
+	if l.length == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// insert inserts the given Element after the given Element.
+func (l *List[T]) insert(e, at *Element[T]) *Element[T] {
+	e.prev, e.next = at, at.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
+	l.length++
+	return e
+}
+
+// unlink removes the given Element from the List without clearing its list pointer.
+func (l *List[T]) unlink(e *Element[T]) {
+	e.prev.next, e.next.prev = e.next, e.prev
+	l.length--
+}
+
+// move moves the given Element so that it is after the given Element.
+func (l *List[T]) move(e, at *Element[T]) {
+	if e == at {
+		return
+	}
+	l.unlink(e)
+	l.insert(e, at)
+}
+
+// PushFront inserts a new Element containing the given value at the front of the List and returns it.
+func (l *List[T]) PushFront(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, &l.root)
+}
+
+// PushBack inserts a new Element containing the given value at the back of the List and returns it.
+func (l *List[T]) PushBack(v T) *Element[T] {
+	l.lazyInit()
+	return l.insert(&Element[T]{Value: v}, l.root.prev)
+}
+
+// InsertBefore inserts a new Element containing the given value immediately before the given Element and returns it.
+// If the given Element is not within the List then the List is not modified and nil is returned.
+func (l *List[T]) InsertBefore(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Element[T]{Value: v}, mark.prev)
+}
+
+// InsertAfter inserts a new Element containing the given value immediately after the given Element and returns it. If
+// the given Element is not within the List then the List is not modified and nil is returned.
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	if mark.list != l {
+		return nil
+	}
+	return l.insert(&Element[T]{Value: v}, mark)
+}
+
+// Remove removes the given Element from the List, if it is within the List, and returns its value.
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e.list == l {
+		l.unlink(e)
+		e.prev, e.next, e.list = nil, nil, nil
+	}
+	return e.Value
+}
+
+// MoveToFront moves the given Element to the front of the List. If the Element is not within the List then the List is
+// not modified.
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if e.list == l {
+		l.move(e, &l.root)
+	}
+}
+
+// MoveToBack moves the given Element to the back of the List. If the Element is not within the List then the List is
+// not modified.
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	if e.list == l {
+		l.move(e, l.root.prev)
+	}
+}
+
+// MoveBefore moves the given Element to immediately before the given mark. If either Element is not within the List,
+// or they are the same Element, then the List is not modified.
+func (l *List[T]) MoveBefore(e, mark *Element[T]) {
+	if e.list == l && mark.list == l && e != mark {
+		l.move(e, mark.prev)
+	}
+}
+
+// MoveAfter moves the given Element to immediately after the given mark. If either Element is not within the List, or
+// they are the same Element, then the List is not modified.
+func (l *List[T]) MoveAfter(e, mark *Element[T]) {
+	if e.list == l && mark.list == l && e != mark {
+		l.move(e, mark)
+	}
+}
+
+// Values returns the values within the List from front to back.
+func (l *List[T]) Values() []T {
+	values := make([]T, 0, l.length)
+	for e := l.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value)
+	}
+	return values
+}
@@ -0,0 +1,297 @@
+package structs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/andygello555/gotils/v2/slices"
+)
+
+// orderedMapEntry is an entry within an OrderedMap. Entries are linked together in a circular doubly linked list.
+type orderedMapEntry[K comparable, V any] struct {
+	key        K
+	val        V
+	prev, next *orderedMapEntry[K, V]
+}
+
+// OrderedMap is a map that remembers the order in which its keys were inserted. It is backed by a map and a doubly
+// linked list, so Set, Get, Delete, MoveToFront, and MoveToBack are all O(1). Setting the value of a key that already
+// exists does not change its position. The zero value is an empty OrderedMap that is ready to use. Like a map, a copy
+// of an OrderedMap shares its contents with the original.
+//
+// OrderedMap implements json.Marshaler and json.Unmarshaler so that JSON objects can be round-tripped without losing
+// the order of their keys. Use OrderedMap.ToMap to pass the contents of an OrderedMap[string, any] to functions that
+// expect a deserialised JSON, such as maps.CopyMap.
+//
+// OrderedMap is not safe for concurrent use.
+type OrderedMap[K comparable, V any] struct {
+	entries map[K]*orderedMapEntry[K, V]
+	// root is the sentinel of the linked list. It is allocated separately so that copying an OrderedMap never leaves
+	// the copy's list pointing at the original's sentinel.
+	root *orderedMapEntry[K, V]
+}
+
+// NewOrderedMap creates a new, empty, OrderedMap.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return new(OrderedMap[K, V]).lazyInit()
+}
+
+// NewOrderedMapFrom creates a new OrderedMap containing the key-value pairs of the given map. As maps are unordered,
+// the keys are inserted in the order given by slices.Order.
+func NewOrderedMapFrom[K comparable, V any](m map[K]V) *OrderedMap[K, V] {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Order(keys)
+
+	om := NewOrderedMap[K, V]()
+	for _, key := range keys {
+		om.Set(key, m[key])
+	}
+	return om
+}
+
+func (om *OrderedMap[K, V]) lazyInit() *OrderedMap[K, V] {
+	if om.entries == nil {
+		om.entries = make(map[K]*orderedMapEntry[K, V])
+		om.root = &orderedMapEntry[K, V]{}
+		om.root.next, om.root.prev = om.root, om.root
+	}
+	return om
+}
+
+func (om *OrderedMap[K, V]) insertAfter(e, at *orderedMapEntry[K, V]) {
+	e.prev, e.next = at, at.next
+	at.next.prev = e
+	at.next = e
+}
+
+func (om *OrderedMap[K, V]) unlink(e *orderedMapEntry[K, V]) {
+	e.prev.next, e.next.prev = e.next, e.prev
+	e.prev, e.next = nil, nil
+}
+
+// Set sets the value for the given key. If the key is new then it is added to the back of the OrderedMap, otherwise
+// its position is unchanged. Returns whether the key is new.
+func (om *OrderedMap[K, V]) Set(key K, val V) bool {
+	om.lazyInit()
+	if e, ok := om.entries[key]; ok {
+		e.val = val
+		return false
+	}
+	e := &orderedMapEntry[K, V]{key: key, val: val}
+	om.entries[key] = e
+	om.insertAfter(e, om.root.prev)
+	return true
+}
+
+// Get returns the value for the given key, and whether the key exists.
+func (om *OrderedMap[K, V]) Get(key K) (val V, ok bool) {
+	e, ok := om.entries[key]
+	if !ok {
+		return
+	}
+	return e.val, true
+}
+
+// Contains returns whether the given key exists.
+func (om *OrderedMap[K, V]) Contains(key K) bool {
+	_, ok := om.entries[key]
+	return ok
+}
+
+// Delete removes the given key. Returns whether the key existed.
+func (om *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := om.entries[key]
+	if ok {
+		om.unlink(e)
+		delete(om.entries, key)
+	}
+	return ok
+}
+
+// MoveToFront moves the given key to the front of the OrderedMap. Returns whether the key exists.
+func (om *OrderedMap[K, V]) MoveToFront(key K) bool {
+	e, ok := om.entries[key]
+	if ok {
+		om.unlink(e)
+		om.insertAfter(e, om.root)
+	}
+	return ok
+}
+
+// MoveToBack moves the given key to the back of the OrderedMap. Returns whether the key exists.
+func (om *OrderedMap[K, V]) MoveToBack(key K) bool {
+	e, ok := om.entries[key]
+	if ok {
+		om.unlink(e)
+		om.insertAfter(e, om.root.prev)
+	}
+	return ok
+}
+
+// Front returns the first key-value pair in the OrderedMap. ok is false if the OrderedMap is empty.
+func (om *OrderedMap[K, V]) Front() (key K, val V, ok bool) {
+	if len(om.entries) == 0 {
+		return
+	}
+	return om.root.next.key, om.root.next.val, true
+}
+
+// Back returns the last key-value pair in the OrderedMap. ok is false if the OrderedMap is empty.
+func (om *OrderedMap[K, V]) Back() (key K, val V, ok bool) {
+	if len(om.entries) == 0 {
+		return
+	}
+	return om.root.prev.key, om.root.prev.val, true
+}
+
+// Len returns the number of key-value pairs in the OrderedMap.
+func (om *OrderedMap[K, V]) Len() int { return len(om.entries) }
+
+// Range calls the given function on each index-key-value triple in order. The function should return whether you want
+// to keep iterating. The function may delete the key that it is currently passed.
+func (om *OrderedMap[K, V]) Range(fun func(i int, key K, val V) bool) {
+	if len(om.entries) == 0 {
+		return
+	}
+	i := 0
+	for e := om.root.next; e != om.root; i++ {
+		next := e.next
+		if !fun(i, e.key, e.val) {
+			break
+		}
+		e = next
+	}
+}
+
+// Keys returns the keys within the OrderedMap in order.
+func (om *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(om.entries))
+	om.Range(func(i int, key K, val V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns the values within the OrderedMap in the order of their keys.
+func (om *OrderedMap[K, V]) Values() []V {
+	values := make([]V, 0, len(om.entries))
+	om.Range(func(i int, key K, val V) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// ToMap returns a plain map containing the key-value pairs in the OrderedMap.
+func (om *OrderedMap[K, V]) ToMap() map[K]V {
+	m := make(map[K]V, len(om.entries))
+	for key, e := range om.entries {
+		m[key] = e.val
+	}
+	return m
+}
+
+// Clear removes every key-value pair from the OrderedMap.
+func (om *OrderedMap[K, V]) Clear() {
+	om.entries = nil
+	om.lazyInit()
+}
+
+// MarshalJSON marshals the OrderedMap as a JSON object whose keys are in order. Keys are marshalled in the same way as
+// encoding/json marshals map keys, so K must be a string or integer type, or implement encoding.TextMarshaler.
+func (om *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	var err error
+	om.Range(func(i int, key K, val V) bool {
+		var keyData, valData []byte
+		if keyData, err = marshalJSONKey(key); err != nil {
+			return false
+		}
+		if valData, err = json.Marshal(val); err != nil {
+			return false
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(keyData)
+		b.WriteByte(':')
+		b.Write(valData)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// marshalJSONKey marshals the given key into a JSON string. The key is marshalled within a single entry map so that
+// encoding/json's rules for map keys are followed exactly.
+func marshalJSONKey[K comparable](key K) ([]byte, error) {
+	data, err := json.Marshal(map[K]struct{}{key: {}})
+	if err != nil {
+		return nil, err
+	}
+	// Strip the leading '{' and the trailing ':{}}'
+	return data[1 : len(data)-4], nil
+}
+
+// unmarshalJSONKey unmarshals the given JSON object key into a K. Like marshalJSONKey, the key is unmarshalled within a
+// single entry map so that encoding/json's rules for map keys are followed exactly.
+func unmarshalJSONKey[K comparable](key string) (k K, err error) {
+	quoted, _ := json.Marshal(key)
+	m := make(map[K]struct{}, 1)
+	if err = json.Unmarshal([]byte("{"+string(quoted)+":{}}"), &m); err != nil {
+		return
+	}
+	for k = range m {
+		break
+	}
+	return
+}
+
+// UnmarshalJSON unmarshals a JSON object into the OrderedMap, replacing its contents. Keys are inserted in the order
+// that they appear within the JSON object. If a key appears more than once then the last value is used but the key
+// keeps its first position. Nested objects are unmarshalled in the same way as encoding/json, so when V is any they
+// become map[string]any.
+func (om *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("cannot unmarshal %v into %T: expected a JSON object", tok, om)
+	}
+
+	om.Clear()
+	for dec.More() {
+		if tok, err = dec.Token(); err != nil {
+			return err
+		}
+
+		var key K
+		if key, err = unmarshalJSONKey[K](tok.(string)); err != nil {
+			return err
+		}
+
+		var val V
+		if err = dec.Decode(&val); err != nil {
+			return err
+		}
+		om.Set(key, val)
+	}
+
+	// Consume the closing delimiter
+	_, err = dec.Token()
+	return err
+}
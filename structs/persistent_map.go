@@ -0,0 +1,303 @@
+package structs
+
+import (
+	"github.com/andygello555/gotils/v2/misc"
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	hamtBits  = 5
+	hamtMask  = 1<<hamtBits - 1
+	hamtDepth = 64
+)
+
+// hamtEntry is an entry within a hamtNode. It is either a key-value pair, or a child node if node is not nil.
+type hamtEntry[K comparable, V any] struct {
+	hash uint64
+	key  K
+	val  V
+	node *hamtNode[K, V]
+}
+
+// hamtNode is a node within the hash array mapped trie of a PersistentMap. The bitmap records which of the 32 possible
+// slots are occupied, and entries holds the occupied slots in order. Once every bit of the hash has been used, keys
+// whose hashes collide are stored in a collision node, which holds its entries in a plain list. Nodes are never
+// modified once they are reachable from a PersistentMap.
+type hamtNode[K comparable, V any] struct {
+	bitmap    uint32
+	entries   []hamtEntry[K, V]
+	collision bool
+}
+
+// slot returns the bit for the given hash at the given shift, and the index of that bit within the entries.
+func (n *hamtNode[K, V]) slot(hash uint64, shift uint) (bit uint32, i int) {
+	bit = 1 << ((hash >> shift) & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// clone returns a copy of the node with the entry at the given index replaced.
+func (n *hamtNode[K, V]) clone(i int, entry hamtEntry[K, V]) *hamtNode[K, V] {
+	c := &hamtNode[K, V]{bitmap: n.bitmap, entries: append([]hamtEntry[K, V](nil), n.entries...), collision: n.collision}
+	c.entries[i] = entry
+	return c
+}
+
+// insert returns a copy of the node with the given entry inserted at the given index.
+func (n *hamtNode[K, V]) insert(bit uint32, i int, entry hamtEntry[K, V]) *hamtNode[K, V] {
+	entries := make([]hamtEntry[K, V], 0, len(n.entries)+1)
+	entries = append(append(append(entries, n.entries[:i]...), entry), n.entries[i:]...)
+	return &hamtNode[K, V]{bitmap: n.bitmap | bit, entries: entries, collision: n.collision}
+}
+
+// remove returns a copy of the node with the entry at the given index removed.
+func (n *hamtNode[K, V]) remove(bit uint32, i int) *hamtNode[K, V] {
+	entries := make([]hamtEntry[K, V], 0, len(n.entries)-1)
+	entries = append(append(entries, n.entries[:i]...), n.entries[i+1:]...)
+	return &hamtNode[K, V]{bitmap: n.bitmap &^ bit, entries: entries, collision: n.collision}
+}
+
+// mergeHAMTEntries creates a node at the given shift that contains both of the given entries, which are known to be in
+// the same slot of the parent node.
+func mergeHAMTEntries[K comparable, V any](shift uint, a, b hamtEntry[K, V]) *hamtNode[K, V] {
+	if shift >= hamtDepth {
+		return &hamtNode[K, V]{entries: []hamtEntry[K, V]{a, b}, collision: true}
+	}
+
+	fragA, fragB := (a.hash>>shift)&hamtMask, (b.hash>>shift)&hamtMask
+	switch {
+	case fragA == fragB:
+		child := mergeHAMTEntries(shift+hamtBits, a, b)
+		return &hamtNode[K, V]{bitmap: 1 << fragA, entries: []hamtEntry[K, V]{{node: child}}}
+	case fragA > fragB:
+		a, b = b, a
+	}
+	return &hamtNode[K, V]{bitmap: 1<<fragA | 1<<fragB, entries: []hamtEntry[K, V]{a, b}}
+}
+
+// set returns a copy of the node with the given entry set, and whether the entry's key is new.
+func (n *hamtNode[K, V]) set(shift uint, entry hamtEntry[K, V]) (*hamtNode[K, V], bool) {
+	if n.collision {
+		for i, e := range n.entries {
+			if e.key == entry.key {
+				return n.clone(i, entry), false
+			}
+		}
+		return n.insert(0, len(n.entries), entry), true
+	}
+
+	bit, i := n.slot(entry.hash, shift)
+	if n.bitmap&bit == 0 {
+		return n.insert(bit, i, entry), true
+	}
+
+	e := n.entries[i]
+	switch {
+	case e.node != nil:
+		child, added := e.node.set(shift+hamtBits, entry)
+		return n.clone(i, hamtEntry[K, V]{node: child}), added
+	case e.key == entry.key:
+		return n.clone(i, entry), false
+	default:
+		return n.clone(i, hamtEntry[K, V]{node: mergeHAMTEntries(shift+hamtBits, e, entry)}), true
+	}
+}
+
+// delete returns a copy of the node with the given key removed, and whether the key existed. If the copy contains a
+// single key-value pair and no children, it can be inlined into its parent.
+func (n *hamtNode[K, V]) delete(shift uint, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if n.collision {
+		for i, e := range n.entries {
+			if e.key == key {
+				return n.remove(0, i), true
+			}
+		}
+		return n, false
+	}
+
+	bit, i := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	e := n.entries[i]
+	if e.node == nil {
+		if e.key != key {
+			return n, false
+		}
+		return n.remove(bit, i), true
+	}
+
+	child, deleted := e.node.delete(shift+hamtBits, hash, key)
+	switch {
+	case !deleted:
+		return n, false
+	case len(child.entries) == 0:
+		return n.remove(bit, i), true
+	case len(child.entries) == 1 && child.entries[0].node == nil:
+		// Pull the last key-value pair up into this node
+		return n.clone(i, child.entries[0]), true
+	default:
+		return n.clone(i, hamtEntry[K, V]{node: child}), true
+	}
+}
+
+// get returns the entry for the given key, and whether it exists.
+func (n *hamtNode[K, V]) get(hash uint64, key K) (*hamtEntry[K, V], bool) {
+	for shift := uint(0); ; shift += hamtBits {
+		if n.collision {
+			for i := range n.entries {
+				if n.entries[i].key == key {
+					return &n.entries[i], true
+				}
+			}
+			return nil, false
+		}
+
+		bit, i := n.slot(hash, shift)
+		if n.bitmap&bit == 0 {
+			return nil, false
+		}
+		e := &n.entries[i]
+		if e.node == nil {
+			return e, e.key == key
+		}
+		n = e.node
+	}
+}
+
+// rangeNode calls the given function on each key-value pair within the subtree rooted at the node. Returns false if
+// iteration should stop.
+func (n *hamtNode[K, V]) rangeNode(i *int, fun func(i int, key K, val V) bool) bool {
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.rangeNode(i, fun) {
+				return false
+			}
+			continue
+		}
+		if !fun(*i, e.key, e.val) {
+			return false
+		}
+		*i++
+	}
+	return true
+}
+
+// PersistentMap is an immutable map. Every update returns a new PersistentMap that shares most of its structure with
+// the original, so taking a snapshot is O(1) and older versions remain valid after an update.
+//
+// PersistentMap is a hash array mapped trie (HAMT). Keys are hashed using misc.Hash, and each level of the trie is
+// indexed by 5 bits of the hash, so Get, Set, and Delete are O(log32 n). Keys are iterated over in an order that is
+// determined by their hashes, which is consistent between the versions of a PersistentMap but not between
+// PersistentMap(s) created using separate calls to NewPersistentMap.
+//
+// As a PersistentMap is never modified after it has been created, it is safe for concurrent use. The zero value is an
+// empty PersistentMap that is ready to use. Its seed is chosen when the first key is set.
+type PersistentMap[K comparable, V any] struct {
+	seed maphash.Seed
+	root *hamtNode[K, V]
+	size int
+}
+
+// NewPersistentMap creates a new, empty, PersistentMap.
+func NewPersistentMap[K comparable, V any]() *PersistentMap[K, V] {
+	return &PersistentMap[K, V]{}
+}
+
+// NewPersistentMapFrom creates a new PersistentMap containing the key-value pairs of the given map.
+func NewPersistentMapFrom[K comparable, V any](m map[K]V) *PersistentMap[K, V] {
+	pm := NewPersistentMap[K, V]()
+	for key, val := range m {
+		pm = pm.Set(key, val)
+	}
+	return pm
+}
+
+// Len returns the number of key-value pairs in the PersistentMap.
+func (pm *PersistentMap[K, V]) Len() int { return pm.size }
+
+// Get returns the value for the given key, and whether the key exists.
+func (pm *PersistentMap[K, V]) Get(key K) (val V, ok bool) {
+	if pm.size == 0 {
+		return
+	}
+	if e, ok := pm.root.get(misc.Hash(pm.seed, key), key); ok {
+		return e.val, true
+	}
+	return
+}
+
+// Contains returns whether the given key exists.
+func (pm *PersistentMap[K, V]) Contains(key K) bool {
+	_, ok := pm.Get(key)
+	return ok
+}
+
+// Set returns a new PersistentMap with the value for the given key set.
+func (pm *PersistentMap[K, V]) Set(key K, val V) *PersistentMap[K, V] {
+	seed, root := pm.seed, pm.root
+	// The seed and root are created lazily so that the zero value is ready to use
+	if root == nil {
+		seed, root = maphash.MakeSeed(), &hamtNode[K, V]{}
+	}
+	root, added := root.set(0, hamtEntry[K, V]{hash: misc.Hash(seed, key), key: key, val: val})
+	npm := &PersistentMap[K, V]{seed: seed, root: root, size: pm.size}
+	if added {
+		npm.size++
+	}
+	return npm
+}
+
+// Delete returns a new PersistentMap with the given key removed. If the key does not exist then the PersistentMap is
+// returned as is.
+func (pm *PersistentMap[K, V]) Delete(key K) *PersistentMap[K, V] {
+	if pm.size == 0 {
+		return pm
+	}
+	root, deleted := pm.root.delete(0, misc.Hash(pm.seed, key), key)
+	if !deleted {
+		return pm
+	}
+	return &PersistentMap[K, V]{seed: pm.seed, root: root, size: pm.size - 1}
+}
+
+// Range calls the given function on each index-key-value triple within the PersistentMap. The function should return
+// whether you want to keep iterating.
+func (pm *PersistentMap[K, V]) Range(fun func(i int, key K, val V) bool) {
+	if pm.root == nil {
+		return
+	}
+	i := 0
+	pm.root.rangeNode(&i, fun)
+}
+
+// Keys returns the keys within the PersistentMap in iteration order.
+func (pm *PersistentMap[K, V]) Keys() []K {
+	keys := make([]K, 0, pm.size)
+	pm.Range(func(i int, key K, val V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns the values within the PersistentMap in iteration order.
+func (pm *PersistentMap[K, V]) Values() []V {
+	values := make([]V, 0, pm.size)
+	pm.Range(func(i int, key K, val V) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// ToMap returns the key-value pairs within the PersistentMap as a new map.
+func (pm *PersistentMap[K, V]) ToMap() map[K]V {
+	m := make(map[K]V, pm.size)
+	pm.Range(func(i int, key K, val V) bool {
+		m[key] = val
+		return true
+	})
+	return m
+}
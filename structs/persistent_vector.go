@@ -0,0 +1,239 @@
+package structs
+
+import "fmt"
+
+const (
+	vectorBits  = 5
+	vectorWidth = 1 << vectorBits
+	vectorMask  = vectorWidth - 1
+)
+
+// vectorNode is a node within the trie of a PersistentVector. Leaf nodes hold vectorWidth values, whilst internal
+// nodes hold up to vectorWidth children. Nodes are never modified once they are reachable from a PersistentVector.
+type vectorNode[T any] struct {
+	children []*vectorNode[T]
+	values   []T
+}
+
+// clone returns a shallow copy of the internal node. A nil node is cloned as an empty internal node.
+func (n *vectorNode[T]) clone() *vectorNode[T] {
+	c := &vectorNode[T]{children: make([]*vectorNode[T], 0, vectorWidth)}
+	if n != nil {
+		c.children = append(c.children, n.children...)
+	}
+	return c
+}
+
+// PersistentVector is an immutable slice. Every update returns a new PersistentVector that shares most of its
+// structure with the original, so taking a snapshot is O(1) and older versions remain valid after an update.
+//
+// Values are stored in a trie with a branching factor of 32 alongside a tail of up to 32 values. At, Set, and Pop are
+// O(log32 n), and Append is amortised O(1) per value. The zero value is an empty PersistentVector that is ready to use.
+//
+// As a PersistentVector is never modified after it has been created, it is safe for concurrent use.
+type PersistentVector[T any] struct {
+	size  int
+	shift uint
+	root  *vectorNode[T]
+	tail  []T
+}
+
+// NewPersistentVector creates a new PersistentVector containing the given values.
+func NewPersistentVector[T any](values ...T) *PersistentVector[T] {
+	return (&PersistentVector[T]{}).Append(values...)
+}
+
+// Len returns the number of values in the PersistentVector.
+func (v *PersistentVector[T]) Len() int { return v.size }
+
+// tailOffset returns the number of values that are stored within the trie rather than the tail.
+func (v *PersistentVector[T]) tailOffset() int { return v.size - len(v.tail) }
+
+// leaf returns the values of the leaf that contains the ith value. i must be less than the tail offset.
+func (v *PersistentVector[T]) leaf(i int) []T {
+	n := v.root
+	for level := v.shift; level > 0; level -= vectorBits {
+		n = n.children[(i>>level)&vectorMask]
+	}
+	return n.values
+}
+
+func (v *PersistentVector[T]) checkIndex(i int) {
+	if i < 0 || i >= v.size {
+		panic(fmt.Errorf("index %d out of range for persistent vector of length %d", i, v.size))
+	}
+}
+
+// At returns the value at the given index. At panics if i is out of range.
+func (v *PersistentVector[T]) At(i int) T {
+	v.checkIndex(i)
+	if offset := v.tailOffset(); i >= offset {
+		return v.tail[i-offset]
+	}
+	return v.leaf(i)[i&vectorMask]
+}
+
+// Set returns a new PersistentVector with the value at the given index replaced. If i is equal to the length of the
+// PersistentVector then the value is appended. Set panics if i is otherwise out of range.
+func (v *PersistentVector[T]) Set(i int, val T) *PersistentVector[T] {
+	if i == v.size {
+		return v.Append(val)
+	}
+	v.checkIndex(i)
+
+	nv := *v
+	if offset := v.tailOffset(); i >= offset {
+		nv.tail = append(make([]T, 0, vectorWidth), v.tail...)
+		nv.tail[i-offset] = val
+	} else {
+		nv.root = v.set(v.shift, v.root, i, val)
+	}
+	return &nv
+}
+
+// set copies the path to the ith value and replaces it with the given value.
+func (v *PersistentVector[T]) set(level uint, n *vectorNode[T], i int, val T) *vectorNode[T] {
+	if level == 0 {
+		c := &vectorNode[T]{values: append(make([]T, 0, vectorWidth), n.values...)}
+		c.values[i&vectorMask] = val
+		return c
+	}
+	c := n.clone()
+	sub := (i >> level) & vectorMask
+	c.children[sub] = v.set(level-vectorBits, n.children[sub], i, val)
+	return c
+}
+
+// Append returns a new PersistentVector with the given values added to the end.
+func (v *PersistentVector[T]) Append(values ...T) *PersistentVector[T] {
+	if len(values) == 0 {
+		return v
+	}
+
+	// The copied tail belongs to the new PersistentVector, so it can be appended to in place until it is full
+	nv := *v
+	nv.tail = append(make([]T, 0, vectorWidth), v.tail...)
+	for _, val := range values {
+		if len(nv.tail) == vectorWidth {
+			nv.pushTail()
+			nv.tail = make([]T, 0, vectorWidth)
+		}
+		nv.tail = append(nv.tail, val)
+		nv.size++
+	}
+	return &nv
+}
+
+// pushTail moves the full tail into the trie, adding a new level to the trie if it is full.
+func (v *PersistentVector[T]) pushTail() {
+	leaf := &vectorNode[T]{values: v.tail}
+	offset := v.tailOffset()
+	switch {
+	case v.root == nil:
+		v.shift = vectorBits
+		v.root = v.push(v.shift, nil, leaf, offset)
+	case offset>>vectorBits >= 1<<v.shift:
+		v.root = &vectorNode[T]{children: []*vectorNode[T]{v.root, v.push(v.shift, nil, leaf, offset)}}
+		v.shift += vectorBits
+	default:
+		v.root = v.push(v.shift, v.root, leaf, offset)
+	}
+}
+
+// push copies the path to the leaf that starts at the ith value, creating any nodes that do not exist, and adds the
+// leaf to it.
+func (v *PersistentVector[T]) push(level uint, n *vectorNode[T], leaf *vectorNode[T], i int) *vectorNode[T] {
+	c := n.clone()
+	if level == vectorBits {
+		c.children = append(c.children, leaf)
+	} else if sub := (i >> level) & vectorMask; sub < len(c.children) {
+		c.children[sub] = v.push(level-vectorBits, c.children[sub], leaf, i)
+	} else {
+		c.children = append(c.children, v.push(level-vectorBits, nil, leaf, i))
+	}
+	return c
+}
+
+// Pop returns a new PersistentVector with the last value removed, as well as the removed value. ok is false if the
+// PersistentVector is empty.
+func (v *PersistentVector[T]) Pop() (popped *PersistentVector[T], val T, ok bool) {
+	switch {
+	case v.size == 0:
+		return v, val, false
+	case v.size == 1:
+		return &PersistentVector[T]{}, v.tail[0], true
+	}
+
+	val = v.tail[len(v.tail)-1]
+	nv := *v
+	nv.size--
+	if len(v.tail) > 1 {
+		nv.tail = v.tail[: len(v.tail)-1 : len(v.tail)-1]
+		return &nv, val, true
+	}
+
+	// The tail is now empty so the last leaf in the trie becomes the new tail
+	nv.tail = v.leaf(v.size - 2)
+	nv.root = v.pop(v.shift, v.root, v.size-2)
+	switch {
+	case nv.root == nil:
+		nv.shift = 0
+	case nv.shift > vectorBits && len(nv.root.children) == 1:
+		nv.root = nv.root.children[0]
+		nv.shift -= vectorBits
+	}
+	return &nv, val, true
+}
+
+// pop copies the path to the leaf that contains the ith value, which is the last value in the trie, and removes the
+// leaf. Returns nil if the node no longer has any children.
+func (v *PersistentVector[T]) pop(level uint, n *vectorNode[T], i int) *vectorNode[T] {
+	sub := (i >> level) & vectorMask
+	if level > vectorBits {
+		child := v.pop(level-vectorBits, n.children[sub], i)
+		if child == nil && sub == 0 {
+			return nil
+		}
+		c := n.clone()
+		if child == nil {
+			c.children = c.children[:sub]
+		} else {
+			c.children[sub] = child
+		}
+		return c
+	}
+	if sub == 0 {
+		return nil
+	}
+	c := n.clone()
+	c.children = c.children[:sub]
+	return c
+}
+
+// Range calls the given function on each index-value pair within the PersistentVector in order. The function should
+// return whether you want to keep iterating.
+func (v *PersistentVector[T]) Range(fun func(i int, val T) bool) {
+	offset := v.tailOffset()
+	for i := 0; i < offset; i += vectorWidth {
+		for j, val := range v.leaf(i) {
+			if !fun(i+j, val) {
+				return
+			}
+		}
+	}
+	for j, val := range v.tail {
+		if !fun(offset+j, val) {
+			return
+		}
+	}
+}
+
+// Values returns the values within the PersistentVector as a new slice.
+func (v *PersistentVector[T]) Values() []T {
+	values := make([]T, 0, v.size)
+	v.Range(func(i int, val T) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
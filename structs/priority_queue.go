@@ -0,0 +1,207 @@
+package structs
+
+import "golang.org/x/exp/constraints"
+
+// PriorityQueueItem is a handle to a value that has been pushed onto a PriorityQueue. It can be passed to
+// PriorityQueue.Update, PriorityQueue.Fix, and PriorityQueue.Remove.
+type PriorityQueueItem[T any] struct {
+	// Value is the value of the item. If Value is modified in-place then PriorityQueue.Fix must be called afterwards.
+	Value T
+	index int
+	seq   uint64
+}
+
+// Queued returns whether the item is still within the PriorityQueue that it was pushed onto.
+func (item *PriorityQueueItem[T]) Queued() bool { return item.index >= 0 }
+
+// PriorityQueue is a binary heap of values that are ordered using a less function. Unlike Heap, values can be of any
+// type, and are pushed and popped without being boxed into an interface, so container/heap is not required. Values
+// that are equal according to the less function are popped in the order that they were pushed.
+//
+// Push, Pop, Update, Fix, and Remove are all O(log n). Peek and Len are O(1).
+//
+// PriorityQueue is not safe for concurrent use.
+type PriorityQueue[T any] struct {
+	items []*PriorityQueueItem[T]
+	less  func(a, b T) bool
+	seq   uint64
+}
+
+// NewPriorityQueue creates a new min-PriorityQueue which pops the value that is least according to the given less
+// function first.
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// NewMaxPriorityQueue creates a new max-PriorityQueue which pops the value that is greatest according to the given less
+// function first.
+func NewMaxPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return NewPriorityQueue(func(a, b T) bool { return less(b, a) })
+}
+
+// NewOrderedPriorityQueue creates a new PriorityQueue for constraints.Ordered values. If max is true then the greatest
+// value is popped first, otherwise the least value is popped first.
+func NewOrderedPriorityQueue[T constraints.Ordered](max bool) *PriorityQueue[T] {
+	if max {
+		return NewPriorityQueue(func(a, b T) bool { return a > b })
+	}
+	return NewPriorityQueue(func(a, b T) bool { return a < b })
+}
+
+// Len returns the number of values in the PriorityQueue.
+func (pq *PriorityQueue[T]) Len() int { return len(pq.items) }
+
+// Push pushes the given value onto the PriorityQueue and returns a handle to it.
+func (pq *PriorityQueue[T]) Push(value T) *PriorityQueueItem[T] {
+	item := &PriorityQueueItem[T]{Value: value, index: len(pq.items), seq: pq.seq}
+	pq.seq++
+	pq.items = append(pq.items, item)
+	pq.up(item.index)
+	return item
+}
+
+// Peek returns the value that would be popped next without removing it. ok is false if the PriorityQueue is empty.
+func (pq *PriorityQueue[T]) Peek() (value T, ok bool) {
+	if len(pq.items) == 0 {
+		return
+	}
+	return pq.items[0].Value, true
+}
+
+// PeekItem returns the handle to the value that would be popped next, or nil if the PriorityQueue is empty.
+func (pq *PriorityQueue[T]) PeekItem() *PriorityQueueItem[T] {
+	if len(pq.items) == 0 {
+		return nil
+	}
+	return pq.items[0]
+}
+
+// Pop removes and returns the next value from the PriorityQueue. ok is false if the PriorityQueue is empty.
+func (pq *PriorityQueue[T]) Pop() (value T, ok bool) {
+	if len(pq.items) == 0 {
+		return
+	}
+	return pq.removeAt(0).Value, true
+}
+
+// Update sets the value of the given item and restores the ordering of the PriorityQueue. Returns false if the item is
+// no longer queued.
+func (pq *PriorityQueue[T]) Update(item *PriorityQueueItem[T], value T) bool {
+	if !pq.contains(item) {
+		return false
+	}
+	item.Value = value
+	pq.fix(item.index)
+	return true
+}
+
+// Fix restores the ordering of the PriorityQueue after the value of the given item has been modified in-place. Returns
+// false if the item is no longer queued.
+func (pq *PriorityQueue[T]) Fix(item *PriorityQueueItem[T]) bool {
+	if !pq.contains(item) {
+		return false
+	}
+	pq.fix(item.index)
+	return true
+}
+
+// Remove removes the given item from the PriorityQueue and returns its value. ok is false if the item is no longer
+// queued.
+func (pq *PriorityQueue[T]) Remove(item *PriorityQueueItem[T]) (value T, ok bool) {
+	if !pq.contains(item) {
+		return
+	}
+	return pq.removeAt(item.index).Value, true
+}
+
+// Drain pops every value from the PriorityQueue and returns them in the order that they were popped.
+func (pq *PriorityQueue[T]) Drain() []T {
+	values := make([]T, 0, len(pq.items))
+	for len(pq.items) > 0 {
+		values = append(values, pq.removeAt(0).Value)
+	}
+	return values
+}
+
+// Clear removes every value from the PriorityQueue.
+func (pq *PriorityQueue[T]) Clear() {
+	for _, item := range pq.items {
+		item.index = -1
+	}
+	pq.items = nil
+}
+
+func (pq *PriorityQueue[T]) contains(item *PriorityQueueItem[T]) bool {
+	return item != nil && item.index >= 0 && item.index < len(pq.items) && pq.items[item.index] == item
+}
+
+func (pq *PriorityQueue[T]) lessAt(i, j int) bool {
+	a, b := pq.items[i], pq.items[j]
+	switch {
+	case pq.less(a.Value, b.Value):
+		return true
+	case pq.less(b.Value, a.Value):
+		return false
+	default:
+		return a.seq < b.seq
+	}
+}
+
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *PriorityQueue[T]) up(j int) {
+	for j > 0 {
+		i := (j - 1) / 2
+		if !pq.lessAt(j, i) {
+			break
+		}
+		pq.swap(i, j)
+		j = i
+	}
+}
+
+func (pq *PriorityQueue[T]) down(i int) bool {
+	start := i
+	n := len(pq.items)
+	for {
+		j := 2*i + 1
+		if j >= n {
+			break
+		}
+		if right := j + 1; right < n && pq.lessAt(right, j) {
+			j = right
+		}
+		if !pq.lessAt(j, i) {
+			break
+		}
+		pq.swap(i, j)
+		i = j
+	}
+	return i > start
+}
+
+func (pq *PriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+// removeAt removes the item at the given index and returns it.
+func (pq *PriorityQueue[T]) removeAt(i int) *PriorityQueueItem[T] {
+	n := len(pq.items) - 1
+	if i != n {
+		pq.swap(i, n)
+	}
+	item := pq.items[n]
+	pq.items[n] = nil
+	pq.items = pq.items[:n]
+	if i != n {
+		pq.fix(i)
+	}
+	item.index = -1
+	return item
+}
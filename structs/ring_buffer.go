@@ -0,0 +1,163 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRingBufferFull is returned when pushing onto a RingBuffer that is full and that is not in overwrite mode.
+var ErrRingBufferFull = errors.New("ring buffer is full")
+
+// RingBuffer is a fixed capacity double-ended queue that is backed by a circular buffer. When the RingBuffer is full,
+// pushes either fail with ErrRingBufferFull or, in overwrite mode, overwrite the value at the opposite end. All
+// operations apart from RingBuffer.Resize and RingBuffer.Values are O(1).
+//
+// RingBuffer is not safe for concurrent use.
+type RingBuffer[T any] struct {
+	buf       []T
+	head      int
+	length    int
+	overwrite bool
+}
+
+// NewRingBuffer creates a new RingBuffer with the given capacity. If overwrite is true then pushing onto a full
+// RingBuffer overwrites the value at the opposite end, rather than returning ErrRingBufferFull. NewRingBuffer panics if
+// capacity < 1.
+func NewRingBuffer[T any](capacity int, overwrite bool) *RingBuffer[T] {
+	if capacity < 1 {
+		panic(fmt.Errorf("ring buffer capacity must be positive, not %d", capacity))
+	}
+	return &RingBuffer[T]{buf: make([]T, capacity), overwrite: overwrite}
+}
+
+// Len returns the number of values in the RingBuffer.
+func (rb *RingBuffer[T]) Len() int { return rb.length }
+
+// Cap returns the capacity of the RingBuffer.
+func (rb *RingBuffer[T]) Cap() int { return len(rb.buf) }
+
+// Full returns whether the RingBuffer is at capacity.
+func (rb *RingBuffer[T]) Full() bool { return rb.length == len(rb.buf) }
+
+// index converts the given logical index into an index within the buffer.
+func (rb *RingBuffer[T]) index(i int) int { return (rb.head + i) % len(rb.buf) }
+
+// PushBack pushes the given value onto the back of the RingBuffer. If the RingBuffer is full then the value at the
+// front is overwritten in overwrite mode, otherwise ErrRingBufferFull is returned.
+func (rb *RingBuffer[T]) PushBack(v T) error {
+	if rb.Full() {
+		if !rb.overwrite {
+			return ErrRingBufferFull
+		}
+		rb.PopFront()
+	}
+	rb.buf[rb.index(rb.length)] = v
+	rb.length++
+	return nil
+}
+
+// PushFront pushes the given value onto the front of the RingBuffer. If the RingBuffer is full then the value at the
+// back is overwritten in overwrite mode, otherwise ErrRingBufferFull is returned.
+func (rb *RingBuffer[T]) PushFront(v T) error {
+	if rb.Full() {
+		if !rb.overwrite {
+			return ErrRingBufferFull
+		}
+		rb.PopBack()
+	}
+	rb.head = (rb.head - 1 + len(rb.buf)) % len(rb.buf)
+	rb.buf[rb.head] = v
+	rb.length++
+	return nil
+}
+
+// PopFront removes and returns the value at the front of the RingBuffer. ok is false if the RingBuffer is empty.
+func (rb *RingBuffer[T]) PopFront() (v T, ok bool) {
+	if rb.length == 0 {
+		return
+	}
+	var zero T
+	v = rb.buf[rb.head]
+	// Zero the popped slot so that we don't keep a reference to the value around
+	rb.buf[rb.head] = zero
+	rb.head = rb.index(1)
+	rb.length--
+	return v, true
+}
+
+// PopBack removes and returns the value at the back of the RingBuffer. ok is false if the RingBuffer is empty.
+func (rb *RingBuffer[T]) PopBack() (v T, ok bool) {
+	if rb.length == 0 {
+		return
+	}
+	var zero T
+	i := rb.index(rb.length - 1)
+	v = rb.buf[i]
+	rb.buf[i] = zero
+	rb.length--
+	return v, true
+}
+
+// Front returns the value at the front of the RingBuffer. ok is false if the RingBuffer is empty.
+func (rb *RingBuffer[T]) Front() (v T, ok bool) {
+	if rb.length == 0 {
+		return
+	}
+	return rb.buf[rb.head], true
+}
+
+// Back returns the value at the back of the RingBuffer. ok is false if the RingBuffer is empty.
+func (rb *RingBuffer[T]) Back() (v T, ok bool) {
+	if rb.length == 0 {
+		return
+	}
+	return rb.buf[rb.index(rb.length-1)], true
+}
+
+// At returns the value at the given index, where index 0 is the front of the RingBuffer. At panics if the index is out
+// of range.
+func (rb *RingBuffer[T]) At(i int) T {
+	if i < 0 || i >= rb.length {
+		panic(fmt.Errorf("index %d out of range for ring buffer of length %d", i, rb.length))
+	}
+	return rb.buf[rb.index(i)]
+}
+
+// Resize changes the capacity of the RingBuffer. If the new capacity is smaller than the number of values in the
+// RingBuffer, then values are dropped from the front. Resize panics if capacity < 1.
+func (rb *RingBuffer[T]) Resize(capacity int) {
+	if capacity < 1 {
+		panic(fmt.Errorf("ring buffer capacity must be positive, not %d", capacity))
+	}
+	for rb.length > capacity {
+		rb.PopFront()
+	}
+
+	buf := make([]T, capacity)
+	if rb.head+rb.length <= len(rb.buf) {
+		copy(buf, rb.buf[rb.head:rb.head+rb.length])
+	} else {
+		n := copy(buf, rb.buf[rb.head:])
+		copy(buf[n:], rb.buf[:rb.length-n])
+	}
+	rb.buf = buf
+	rb.head = 0
+}
+
+// Clear removes every value from the RingBuffer.
+func (rb *RingBuffer[T]) Clear() {
+	var zero T
+	for i := range rb.buf {
+		rb.buf[i] = zero
+	}
+	rb.head, rb.length = 0, 0
+}
+
+// Values returns the values within the RingBuffer from front to back.
+func (rb *RingBuffer[T]) Values() []T {
+	values := make([]T, rb.length)
+	for i := range values {
+		values[i] = rb.buf[rb.index(i)]
+	}
+	return values
+}
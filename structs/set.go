@@ -0,0 +1,174 @@
+package structs
+
+import (
+	"encoding/json"
+	"github.com/andygello555/gotils/v2/slices"
+)
+
+// Set is an unordered collection of unique comparable values. As Set is a map type, it can be passed to any of the
+// functions in the maps package. For instance, maps.OrderedKeys can be used to iterate over a Set whose values are
+// constraints.Ordered in order.
+//
+// Like the functions in the maps package, operations that have no "New" suffix modify the Set in-place, whereas
+// operations with the "New" suffix return a new Set and leave the Set untouched.
+type Set[T comparable] map[T]struct{}
+
+// NewSet creates a new Set containing the given values.
+func NewSet[T comparable](values ...T) Set[T] {
+	s := make(Set[T], len(values))
+	s.Add(values...)
+	return s
+}
+
+// Add adds the given values to the Set.
+func (s Set[T]) Add(values ...T) {
+	for _, value := range values {
+		s[value] = struct{}{}
+	}
+}
+
+// Remove removes the given values from the Set.
+func (s Set[T]) Remove(values ...T) {
+	for _, value := range values {
+		delete(s, value)
+	}
+}
+
+// Contains returns whether the given value is in the Set.
+func (s Set[T]) Contains(value T) bool {
+	_, ok := s[value]
+	return ok
+}
+
+// Len returns the number of values in the Set.
+func (s Set[T]) Len() int { return len(s) }
+
+// Values returns the values within the Set. The values are unordered.
+func (s Set[T]) Values() []T {
+	values := make([]T, 0, len(s))
+	for value := range s {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Clone returns a copy of the Set.
+func (s Set[T]) Clone() Set[T] {
+	clone := make(Set[T], len(s))
+	for value := range s {
+		clone[value] = struct{}{}
+	}
+	return clone
+}
+
+// Union adds every value in the other Sets to the Set.
+func (s Set[T]) Union(others ...Set[T]) {
+	for _, other := range others {
+		for value := range other {
+			s[value] = struct{}{}
+		}
+	}
+}
+
+// UnionNew returns a new Set containing the values that are in the Set or in any of the other Sets.
+func (s Set[T]) UnionNew(others ...Set[T]) Set[T] {
+	union := s.Clone()
+	union.Union(others...)
+	return union
+}
+
+// Intersection removes every value from the Set that is not in all the other Sets.
+func (s Set[T]) Intersection(others ...Set[T]) {
+	for value := range s {
+		for _, other := range others {
+			if !other.Contains(value) {
+				delete(s, value)
+				break
+			}
+		}
+	}
+}
+
+// IntersectionNew returns a new Set containing the values that are in the Set and all the other Sets.
+func (s Set[T]) IntersectionNew(others ...Set[T]) Set[T] {
+	intersection := s.Clone()
+	intersection.Intersection(others...)
+	return intersection
+}
+
+// Difference removes every value from the Set that is in any of the other Sets.
+func (s Set[T]) Difference(others ...Set[T]) {
+	for _, other := range others {
+		for value := range other {
+			delete(s, value)
+		}
+	}
+}
+
+// DifferenceNew returns a new Set containing the values that are in the Set but are not in any of the other Sets.
+func (s Set[T]) DifferenceNew(others ...Set[T]) Set[T] {
+	difference := s.Clone()
+	difference.Difference(others...)
+	return difference
+}
+
+// SymmetricDifference modifies the Set so that it contains only the values that are in either the Set or the other
+// Set, but not both.
+func (s Set[T]) SymmetricDifference(other Set[T]) {
+	for value := range other {
+		if s.Contains(value) {
+			delete(s, value)
+		} else {
+			s[value] = struct{}{}
+		}
+	}
+}
+
+// SymmetricDifferenceNew returns a new Set containing the values that are in either the Set or the other Set, but not
+// both.
+func (s Set[T]) SymmetricDifferenceNew(other Set[T]) Set[T] {
+	difference := s.Clone()
+	difference.SymmetricDifference(other)
+	return difference
+}
+
+// IsSubset returns whether every value in the Set is also in the other Set.
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for value := range s {
+		if !other.Contains(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns whether every value in the other Set is also in the Set.
+func (s Set[T]) IsSuperset(other Set[T]) bool { return other.IsSubset(s) }
+
+// Equal returns whether the Set and the other Set contain the same values.
+func (s Set[T]) Equal(other Set[T]) bool { return len(s) == len(other) && s.IsSubset(other) }
+
+// MarshalJSON marshals the Set as a JSON array. The values are sorted using slices.Order so that the output is
+// deterministic.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	values := s.Values()
+	slices.Order(values)
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON unmarshals a JSON array into the Set. Duplicate values within the array are ignored. Like a map, any
+// values that are already in the Set are kept.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	if *s == nil {
+		*s = make(Set[T], len(values))
+	}
+	s.Add(values...)
+	return nil
+}
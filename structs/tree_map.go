@@ -0,0 +1,428 @@
+package structs
+
+import (
+	"github.com/andygello555/gotils/v2/misc"
+	"golang.org/x/exp/constraints"
+)
+
+// treeNode is a node within a TreeMap. size is the number of nodes in the subtree rooted at the node, which allows
+// TreeMap.Rank and TreeMap.Select to run in O(log n).
+type treeNode[K any, V any] struct {
+	key         K
+	val         V
+	left, right *treeNode[K, V]
+	red         bool
+	size        int
+}
+
+func (n *treeNode[K, V]) isRed() bool { return n != nil && n.red }
+
+func (n *treeNode[K, V]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *treeNode[K, V]) flipColours() {
+	n.red = !n.red
+	n.left.red = !n.left.red
+	n.right.red = !n.right.red
+}
+
+func (n *treeNode[K, V]) min() *treeNode[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func (n *treeNode[K, V]) max() *treeNode[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+// TreeMap is a map whose keys are kept in order using a left-leaning red-black tree. Set, Get, Delete, Floor, Ceiling,
+// Min, Max, Rank, and Select are all O(log n), and iterating over the TreeMap in order does not require the keys to be
+// sorted.
+//
+// TreeMap is not safe for concurrent use.
+type TreeMap[K any, V any] struct {
+	root *treeNode[K, V]
+	cmp  func(a, b K) misc.Ordered
+	// augment is called whenever the children of a node change, after its size has been recomputed. It allows other
+	// structures, such as IntervalTree, to maintain extra information about each subtree.
+	augment func(n *treeNode[K, V])
+}
+
+// resize recomputes the size of the given node, and any augmented information, from its children.
+func (tm *TreeMap[K, V]) resize(n *treeNode[K, V]) {
+	n.size = n.left.len() + n.right.len() + 1
+	if tm.augment != nil {
+		tm.augment(n)
+	}
+}
+
+func (tm *TreeMap[K, V]) rotateLeft(n *treeNode[K, V]) *treeNode[K, V] {
+	x := n.right
+	n.right = x.left
+	x.left = n
+	x.red, n.red = n.red, true
+	tm.resize(n)
+	tm.resize(x)
+	return x
+}
+
+func (tm *TreeMap[K, V]) rotateRight(n *treeNode[K, V]) *treeNode[K, V] {
+	x := n.left
+	n.left = x.right
+	x.right = n
+	x.red, n.red = n.red, true
+	tm.resize(n)
+	tm.resize(x)
+	return x
+}
+
+// balance restores the left-leaning red-black invariants on the way back up the tree.
+func (tm *TreeMap[K, V]) balance(n *treeNode[K, V]) *treeNode[K, V] {
+	if n.right.isRed() && !n.left.isRed() {
+		n = tm.rotateLeft(n)
+	}
+	if n.left.isRed() && n.left.left.isRed() {
+		n = tm.rotateRight(n)
+	}
+	if n.left.isRed() && n.right.isRed() {
+		n.flipColours()
+	}
+	tm.resize(n)
+	return n
+}
+
+func (tm *TreeMap[K, V]) moveRedLeft(n *treeNode[K, V]) *treeNode[K, V] {
+	n.flipColours()
+	if n.right.left.isRed() {
+		n.right = tm.rotateRight(n.right)
+		n = tm.rotateLeft(n)
+		n.flipColours()
+	}
+	return n
+}
+
+func (tm *TreeMap[K, V]) moveRedRight(n *treeNode[K, V]) *treeNode[K, V] {
+	n.flipColours()
+	if n.left.left.isRed() {
+		n = tm.rotateRight(n)
+		n.flipColours()
+	}
+	return n
+}
+
+func (tm *TreeMap[K, V]) deleteMin(n *treeNode[K, V]) *treeNode[K, V] {
+	if n.left == nil {
+		return nil
+	}
+	if !n.left.isRed() && !n.left.left.isRed() {
+		n = tm.moveRedLeft(n)
+	}
+	n.left = tm.deleteMin(n.left)
+	return tm.balance(n)
+}
+
+// NewTreeMap creates a new, empty, TreeMap for constraints.Ordered keys. Keys are compared using misc.Compare.
+func NewTreeMap[K constraints.Ordered, V any]() *TreeMap[K, V] {
+	return NewTreeMapFunc[K, V](misc.Compare[K])
+}
+
+// NewTreeMapFunc creates a new, empty, TreeMap whose keys are ordered using the given comparison function.
+func NewTreeMapFunc[K any, V any](cmp func(a, b K) misc.Ordered) *TreeMap[K, V] {
+	return &TreeMap[K, V]{cmp: cmp}
+}
+
+// NewTreeMapFrom creates a new TreeMap containing the key-value pairs of the given map.
+func NewTreeMapFrom[K constraints.Ordered, V any](m map[K]V) *TreeMap[K, V] {
+	tm := NewTreeMap[K, V]()
+	for key, val := range m {
+		tm.Set(key, val)
+	}
+	return tm
+}
+
+// Len returns the number of key-value pairs in the TreeMap.
+func (tm *TreeMap[K, V]) Len() int { return tm.root.len() }
+
+// find returns the node with the given key, or nil if there is no such node.
+func (tm *TreeMap[K, V]) find(key K) *treeNode[K, V] {
+	n := tm.root
+	for n != nil {
+		switch tm.cmp(key, n.key) {
+		case misc.Less:
+			n = n.left
+		case misc.Greater:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Get returns the value for the given key, and whether the key exists.
+func (tm *TreeMap[K, V]) Get(key K) (val V, ok bool) {
+	if n := tm.find(key); n != nil {
+		return n.val, true
+	}
+	return
+}
+
+// Contains returns whether the given key exists.
+func (tm *TreeMap[K, V]) Contains(key K) bool { return tm.find(key) != nil }
+
+// Set sets the value for the given key. Returns whether the key is new.
+func (tm *TreeMap[K, V]) Set(key K, val V) bool {
+	var added bool
+	tm.root = tm.set(tm.root, key, val, &added)
+	tm.root.red = false
+	return added
+}
+
+func (tm *TreeMap[K, V]) set(n *treeNode[K, V], key K, val V, added *bool) *treeNode[K, V] {
+	if n == nil {
+		*added = true
+		n = &treeNode[K, V]{key: key, val: val, red: true}
+		tm.resize(n)
+		return n
+	}
+
+	switch tm.cmp(key, n.key) {
+	case misc.Less:
+		n.left = tm.set(n.left, key, val, added)
+	case misc.Greater:
+		n.right = tm.set(n.right, key, val, added)
+	default:
+		n.val = val
+	}
+	return tm.balance(n)
+}
+
+// Delete removes the given key. Returns whether the key existed.
+func (tm *TreeMap[K, V]) Delete(key K) bool {
+	if !tm.Contains(key) {
+		return false
+	}
+	if !tm.root.left.isRed() && !tm.root.right.isRed() {
+		tm.root.red = true
+	}
+	tm.root = tm.delete(tm.root, key)
+	if tm.root != nil {
+		tm.root.red = false
+	}
+	return true
+}
+
+// delete removes the given key from the subtree rooted at the given node. The key must exist within the subtree.
+func (tm *TreeMap[K, V]) delete(n *treeNode[K, V], key K) *treeNode[K, V] {
+	if tm.cmp(key, n.key) == misc.Less {
+		if !n.left.isRed() && !n.left.left.isRed() {
+			n = tm.moveRedLeft(n)
+		}
+		n.left = tm.delete(n.left, key)
+	} else {
+		if n.left.isRed() {
+			n = tm.rotateRight(n)
+		}
+		if tm.cmp(key, n.key) == misc.Equal && n.right == nil {
+			return nil
+		}
+		if !n.right.isRed() && !n.right.left.isRed() {
+			n = tm.moveRedRight(n)
+		}
+		if tm.cmp(key, n.key) == misc.Equal {
+			// Replace the node with its successor
+			successor := n.right.min()
+			n.key, n.val = successor.key, successor.val
+			n.right = tm.deleteMin(n.right)
+		} else {
+			n.right = tm.delete(n.right, key)
+		}
+	}
+	return tm.balance(n)
+}
+
+// Min returns the key-value pair with the least key. ok is false if the TreeMap is empty.
+func (tm *TreeMap[K, V]) Min() (key K, val V, ok bool) {
+	if tm.root == nil {
+		return
+	}
+	n := tm.root.min()
+	return n.key, n.val, true
+}
+
+// Max returns the key-value pair with the greatest key. ok is false if the TreeMap is empty.
+func (tm *TreeMap[K, V]) Max() (key K, val V, ok bool) {
+	if tm.root == nil {
+		return
+	}
+	n := tm.root.max()
+	return n.key, n.val, true
+}
+
+// Floor returns the key-value pair with the greatest key that is less than or equal to the given key. ok is false if
+// there is no such key.
+func (tm *TreeMap[K, V]) Floor(key K) (floor K, val V, ok bool) {
+	var best *treeNode[K, V]
+	for n := tm.root; n != nil; {
+		switch tm.cmp(key, n.key) {
+		case misc.Less:
+			n = n.left
+		case misc.Greater:
+			best, n = n, n.right
+		default:
+			return n.key, n.val, true
+		}
+	}
+	if best == nil {
+		return
+	}
+	return best.key, best.val, true
+}
+
+// Ceiling returns the key-value pair with the least key that is greater than or equal to the given key. ok is false if
+// there is no such key.
+func (tm *TreeMap[K, V]) Ceiling(key K) (ceiling K, val V, ok bool) {
+	var best *treeNode[K, V]
+	for n := tm.root; n != nil; {
+		switch tm.cmp(key, n.key) {
+		case misc.Less:
+			best, n = n, n.left
+		case misc.Greater:
+			n = n.right
+		default:
+			return n.key, n.val, true
+		}
+	}
+	if best == nil {
+		return
+	}
+	return best.key, best.val, true
+}
+
+// Rank returns the number of keys in the TreeMap that are less than the given key. The key does not need to exist.
+func (tm *TreeMap[K, V]) Rank(key K) int {
+	rank := 0
+	for n := tm.root; n != nil; {
+		switch tm.cmp(key, n.key) {
+		case misc.Less:
+			n = n.left
+		case misc.Greater:
+			rank += n.left.len() + 1
+			n = n.right
+		default:
+			return rank + n.left.len()
+		}
+	}
+	return rank
+}
+
+// Select returns the key-value pair with the given rank, i.e. the key-value pair that has i keys less than it. ok is
+// false if i is out of range.
+func (tm *TreeMap[K, V]) Select(i int) (key K, val V, ok bool) {
+	if i < 0 || i >= tm.Len() {
+		return
+	}
+	n := tm.root
+	for {
+		switch left := n.left.len(); {
+		case i < left:
+			n = n.left
+		case i > left:
+			i -= left + 1
+			n = n.right
+		default:
+			return n.key, n.val, true
+		}
+	}
+}
+
+// Ascend calls the given function on each index-key-value triple in ascending order of key. The function should return
+// whether you want to keep iterating. The TreeMap must not be modified during iteration.
+func (tm *TreeMap[K, V]) Ascend(fun func(i int, key K, val V) bool) {
+	i := 0
+	tm.ascend(tm.root, nil, nil, &i, fun)
+}
+
+// Descend calls the given function on each index-key-value triple in descending order of key. The function should
+// return whether you want to keep iterating. The TreeMap must not be modified during iteration.
+func (tm *TreeMap[K, V]) Descend(fun func(i int, key K, val V) bool) {
+	i := 0
+	tm.descend(tm.root, &i, fun)
+}
+
+// Range calls the given function on each index-key-value triple whose key is within the inclusive range [lo, hi], in
+// ascending order of key. The function should return whether you want to keep iterating. The TreeMap must not be
+// modified during iteration.
+func (tm *TreeMap[K, V]) Range(lo, hi K, fun func(i int, key K, val V) bool) {
+	i := 0
+	tm.ascend(tm.root, &lo, &hi, &i, fun)
+}
+
+// ascend performs an in-order traversal of the subtree rooted at the given node, skipping any subtrees that are
+// entirely outside the given bounds. A nil bound is unbounded. Returns false if iteration should stop.
+func (tm *TreeMap[K, V]) ascend(n *treeNode[K, V], lo, hi *K, i *int, fun func(i int, key K, val V) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := lo == nil || tm.cmp(n.key, *lo) != misc.Less
+	belowHi := hi == nil || tm.cmp(n.key, *hi) != misc.Greater
+	if aboveLo && !tm.ascend(n.left, lo, hi, i, fun) {
+		return false
+	}
+	if aboveLo && belowHi {
+		if !fun(*i, n.key, n.val) {
+			return false
+		}
+		*i++
+	}
+	if belowHi {
+		return tm.ascend(n.right, lo, hi, i, fun)
+	}
+	return true
+}
+
+func (tm *TreeMap[K, V]) descend(n *treeNode[K, V], i *int, fun func(i int, key K, val V) bool) bool {
+	if n == nil {
+		return true
+	}
+	if !tm.descend(n.right, i, fun) {
+		return false
+	}
+	if !fun(*i, n.key, n.val) {
+		return false
+	}
+	*i++
+	return tm.descend(n.left, i, fun)
+}
+
+// Keys returns the keys within the TreeMap in ascending order.
+func (tm *TreeMap[K, V]) Keys() []K {
+	keys := make([]K, 0, tm.Len())
+	tm.Ascend(func(i int, key K, val V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Values returns the values within the TreeMap in ascending order of their keys.
+func (tm *TreeMap[K, V]) Values() []V {
+	values := make([]V, 0, tm.Len())
+	tm.Ascend(func(i int, key K, val V) bool {
+		values = append(values, val)
+		return true
+	})
+	return values
+}
+
+// Clear removes every key-value pair from the TreeMap.
+func (tm *TreeMap[K, V]) Clear() { tm.root = nil }
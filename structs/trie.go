@@ -0,0 +1,214 @@
+package structs
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// trieEdge is an edge from a trieNode to one of its children.
+type trieEdge[V any] struct {
+	r    rune
+	node *trieNode[V]
+}
+
+// trieNode is a node within a Trie. Children are kept sorted by rune so that keys are enumerated in order.
+type trieNode[V any] struct {
+	children []trieEdge[V]
+	val      V
+	ok       bool
+}
+
+// child returns the index of the child for the given rune, and whether it exists. If it does not exist, the index is
+// where the child should be inserted.
+func (n *trieNode[V]) child(r rune) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool { return n.children[i].r >= r })
+	return i, i < len(n.children) && n.children[i].r == r
+}
+
+func (n *trieNode[V]) get(r rune) *trieNode[V] {
+	if i, ok := n.child(r); ok {
+		return n.children[i].node
+	}
+	return nil
+}
+
+// Trie is a prefix tree that maps string keys to values. Keys are split into runes, so each node within the Trie
+// represents a single unicode character rather than a byte. Invalid UTF-8 sequences are treated as utf8.RuneError.
+//
+// Keys are always enumerated in order of their runes. Set, Get, and Delete are O(k) where k is the number of runes in
+// the key.
+//
+// Trie is not safe for concurrent use.
+type Trie[V any] struct {
+	root trieNode[V]
+	size int
+}
+
+// NewTrie creates a new, empty, Trie.
+func NewTrie[V any]() *Trie[V] { return &Trie[V]{} }
+
+// Len returns the number of keys in the Trie.
+func (t *Trie[V]) Len() int { return t.size }
+
+// find returns the node for the given key, or nil if there is no such node.
+func (t *Trie[V]) find(key string) *trieNode[V] {
+	n := &t.root
+	for _, r := range key {
+		if n = n.get(r); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// Set sets the value for the given key. Returns whether the key is new.
+func (t *Trie[V]) Set(key string, val V) bool {
+	n := &t.root
+	for _, r := range key {
+		i, ok := n.child(r)
+		if !ok {
+			n.children = append(n.children, trieEdge[V]{})
+			copy(n.children[i+1:], n.children[i:])
+			n.children[i] = trieEdge[V]{r: r, node: &trieNode[V]{}}
+		}
+		n = n.children[i].node
+	}
+
+	added := !n.ok
+	n.val, n.ok = val, true
+	if added {
+		t.size++
+	}
+	return added
+}
+
+// Get returns the value for the given key, and whether the key exists.
+func (t *Trie[V]) Get(key string) (val V, ok bool) {
+	if n := t.find(key); n != nil && n.ok {
+		return n.val, true
+	}
+	return
+}
+
+// Contains returns whether the given key exists.
+func (t *Trie[V]) Contains(key string) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// HasPrefix returns whether any key within the Trie starts with the given prefix.
+func (t *Trie[V]) HasPrefix(prefix string) bool {
+	n := t.find(prefix)
+	return n != nil && (n.ok || len(n.children) > 0)
+}
+
+// Delete removes the given key. Any nodes that no longer lead to a key are pruned. Returns whether the key existed.
+func (t *Trie[V]) Delete(key string) bool {
+	runes := []rune(key)
+	deleted := t.delete(&t.root, runes)
+	if deleted {
+		t.size--
+	}
+	return deleted
+}
+
+// delete removes the key made up of the given runes from the subtree rooted at the given node.
+func (t *Trie[V]) delete(n *trieNode[V], runes []rune) bool {
+	if len(runes) == 0 {
+		if !n.ok {
+			return false
+		}
+		var zero V
+		n.val, n.ok = zero, false
+		return true
+	}
+
+	i, ok := n.child(runes[0])
+	if !ok {
+		return false
+	}
+	child := n.children[i].node
+	if !t.delete(child, runes[1:]) {
+		return false
+	}
+
+	// Prune the child if it no longer leads to any keys
+	if !child.ok && len(child.children) == 0 {
+		n.children = append(n.children[:i], n.children[i+1:]...)
+	}
+	return true
+}
+
+// LongestPrefix returns the longest key within the Trie that is a prefix of the given string. ok is false if no key
+// is a prefix of the string.
+func (t *Trie[V]) LongestPrefix(s string) (key string, val V, ok bool) {
+	t.Walk(s, func(prefix string, v V) bool {
+		key, val, ok = prefix, v, true
+		return true
+	})
+	return
+}
+
+// Walk traverses the Trie along the runes of the given string, and calls the given function on each key that is a
+// prefix of the string, from shortest to longest. The function should return whether you want to keep walking.
+func (t *Trie[V]) Walk(s string, fun func(prefix string, val V) bool) {
+	n := &t.root
+	if n.ok && !fun("", n.val) {
+		return
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if n = n.get(r); n == nil {
+			return
+		}
+		if n.ok && !fun(s[:i], n.val) {
+			return
+		}
+	}
+}
+
+// RangePrefix calls the given function on each index-key-value triple whose key starts with the given prefix. Keys
+// are visited in order of their runes. The function should return whether you want to keep iterating. The Trie must
+// not be modified during iteration.
+func (t *Trie[V]) RangePrefix(prefix string, fun func(i int, key string, val V) bool) {
+	n := t.find(prefix)
+	if n == nil {
+		return
+	}
+	i := 0
+	t.rangeNode(n, []rune(prefix), &i, fun)
+}
+
+// Range calls the given function on each index-key-value triple within the Trie. Keys are visited in order of their
+// runes. The function should return whether you want to keep iterating. The Trie must not be modified during
+// iteration.
+func (t *Trie[V]) Range(fun func(i int, key string, val V) bool) { t.RangePrefix("", fun) }
+
+func (t *Trie[V]) rangeNode(n *trieNode[V], key []rune, i *int, fun func(i int, key string, val V) bool) bool {
+	if n.ok {
+		if !fun(*i, string(key), n.val) {
+			return false
+		}
+		*i++
+	}
+	for _, edge := range n.children {
+		if !t.rangeNode(edge.node, append(key, edge.r), i, fun) {
+			return false
+		}
+	}
+	return true
+}
+
+// KeysWithPrefix returns the keys that start with the given prefix, in order of their runes.
+func (t *Trie[V]) KeysWithPrefix(prefix string) []string {
+	keys := make([]string, 0)
+	t.RangePrefix(prefix, func(i int, key string, val V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Keys returns every key within the Trie, in order of their runes.
+func (t *Trie[V]) Keys() []string { return t.KeysWithPrefix("") }
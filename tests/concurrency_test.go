@@ -0,0 +1,647 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/andygello555/gotils/v2/concurrency"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUnboundedOverflow(t *testing.T) {
+	for _, test := range []struct {
+		policy          concurrency.OverflowPolicy
+		expectedOutput  []int
+		expectedDropped []int
+		expectedErr     error
+	}{
+		{
+			concurrency.OverflowDropOldest,
+			[]int{7, 8, 9},
+			[]int{0, 1, 2, 3, 4, 5, 6},
+			nil,
+		},
+		{
+			concurrency.OverflowDropNewest,
+			[]int{0, 1, 2},
+			[]int{3, 4, 5, 6, 7, 8, 9},
+			nil,
+		},
+		{
+			concurrency.OverflowError,
+			[]int{0, 1, 2},
+			[]int{3, 4, 5, 6, 7, 8, 9},
+			concurrency.ErrOverflow,
+		},
+	} {
+		dropped := make([]int, 0)
+		u := concurrency.NewUnboundedWithOptions(concurrency.UnboundedOptions[int]{
+			SoftCap:  3,
+			Overflow: test.policy,
+			OnOverflow: func(item int, err error) {
+				if err != test.expectedErr {
+					t.Errorf("%s: got error \"%v\", expected: \"%v\"", test.policy, err, test.expectedErr)
+				}
+				dropped = append(dropped, item)
+			},
+		})
+
+		for i := 0; i < 10; i++ {
+			u.In() <- i
+		}
+		close(u.In())
+
+		output := make([]int, 0)
+		for v := range u.Out() {
+			output = append(output, v)
+		}
+
+		if !reflect.DeepEqual(output, test.expectedOutput) {
+			t.Errorf("%s: got output: \"%v\", expected: \"%v\"", test.policy, output, test.expectedOutput)
+		}
+		if !reflect.DeepEqual(dropped, test.expectedDropped) {
+			t.Errorf("%s: got dropped: \"%v\", expected: \"%v\"", test.policy, dropped, test.expectedDropped)
+		}
+	}
+}
+
+func TestUnboundedOverflowBlock(t *testing.T) {
+	u := concurrency.NewUnboundedWithOptions(concurrency.UnboundedOptions[int]{
+		SoftCap:  5,
+		Overflow: concurrency.OverflowBlock,
+	})
+
+	written := make(chan int)
+	go func() {
+		for i := 0; i < 10; i++ {
+			u.In() <- i
+			written <- i
+		}
+		close(u.In())
+		close(written)
+	}()
+
+	// Only SoftCap values should be written before the writer is blocked
+	for i := 0; i < 5; i++ {
+		<-written
+	}
+	select {
+	case v := <-written:
+		t.Errorf("writer was not blocked after writing %d values, wrote: %d", 5, v)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if u.Len() != 5 {
+		t.Errorf("Got Len: %d, expected: %d", u.Len(), 5)
+	}
+
+	go func() {
+		for range written {
+		}
+	}()
+
+	expected := 0
+	for v := range u.Out() {
+		if v != expected {
+			t.Errorf("Got: %d, expected: %d", v, expected)
+		}
+		expected++
+	}
+	if expected != 10 {
+		t.Errorf("Read %d values, expected: %d", expected, 10)
+	}
+}
+
+func TestUnboundedContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := concurrency.NewUnboundedContext(ctx, concurrency.UnboundedOptions[int]{})
+	for i := 0; i < 10; i++ {
+		u.In() <- i
+	}
+
+	// Nobody is reading from Out, so the goroutine would leak without cancellation
+	cancel()
+	select {
+	case <-u.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Unbounded goroutine did not exit after cancellation")
+	}
+
+	expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if unread := u.Drain(); !reflect.DeepEqual(unread, expected) {
+		t.Errorf("Got unread: \"%v\", expected: \"%v\"", unread, expected)
+	}
+	if unread := u.Drain(); unread != nil {
+		t.Errorf("Got unread after second Drain: \"%v\", expected: nil", unread)
+	}
+	if u.Len() != 0 {
+		t.Errorf("Got Len: %d, expected: %d", u.Len(), 0)
+	}
+}
+
+func TestPool(t *testing.T) {
+	for _, test := range []struct {
+		options concurrency.PoolOptions
+	}{
+		{concurrency.PoolOptions{Workers: 1}},
+		{concurrency.PoolOptions{Workers: 4, QueueSize: 2, Results: concurrency.ResultsUnordered}},
+		{concurrency.PoolOptions{Workers: 8, Unbounded: true, Results: concurrency.ResultsOrdered}},
+	} {
+		pool := concurrency.NewPool(test.options, func(ctx context.Context, in int) (int, error) {
+			if in%10 == 0 {
+				panic(errors.New("multiple of ten"))
+			}
+			return in * 2, nil
+		})
+
+		const jobs = 100
+		futures := make([]*concurrency.Future[int], jobs)
+		results := make(map[int]concurrency.Result[int])
+		var readErr error
+		readDone := make(chan struct{})
+		go func() {
+			defer close(readDone)
+			if test.options.Results == concurrency.ResultsNone {
+				return
+			}
+			last := -1
+			for result := range pool.Results() {
+				if test.options.Results == concurrency.ResultsOrdered && result.Index != last+1 {
+					readErr = errors.New("results are out of order")
+				}
+				last = result.Index
+				results[result.Index] = result
+			}
+		}()
+
+		for i := 0; i < jobs; i++ {
+			var err error
+			if futures[i], err = pool.Submit(i); err != nil {
+				t.Fatalf("%+v: could not submit %d: %v", test.options, i, err)
+			}
+		}
+		if err := pool.Shutdown(context.Background()); err != nil {
+			t.Fatalf("%+v: shutdown returned an error: %v", test.options, err)
+		}
+		if _, err := pool.Submit(jobs); err != concurrency.ErrPoolClosed {
+			t.Errorf("%+v: got error \"%v\" after shutdown, expected: \"%v\"", test.options, err, concurrency.ErrPoolClosed)
+		}
+		<-readDone
+
+		if readErr != nil {
+			t.Errorf("%+v: %v", test.options, readErr)
+		}
+		if test.options.Results != concurrency.ResultsNone && len(results) != jobs {
+			t.Errorf("%+v: got %d results, expected: %d", test.options, len(results), jobs)
+		}
+
+		for i, future := range futures {
+			value, err := future.Wait()
+			if i%10 == 0 {
+				var panicErr *concurrency.PanicError
+				if !errors.As(err, &panicErr) || panicErr.Unwrap().Error() != "multiple of ten" {
+					t.Errorf("%+v: got error \"%v\" for %d, expected a PanicError", test.options, err, i)
+				}
+			} else if err != nil || value != i*2 {
+				t.Errorf("%+v: got %d, %v for %d, expected: %d, nil", test.options, value, err, i, i*2)
+			}
+		}
+	}
+}
+
+func TestPoolShutdownTimeout(t *testing.T) {
+	pool := concurrency.NewPool(concurrency.PoolOptions{Workers: 1, Unbounded: true}, func(ctx context.Context, in int) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	first, _ := pool.Submit(0)
+	second, _ := pool.Submit(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Got error: \"%v\", expected: \"%v\"", err, context.DeadlineExceeded)
+	}
+	<-pool.Done()
+
+	for _, future := range []*concurrency.Future[int]{first, second} {
+		if _, err := future.Wait(); err != context.Canceled {
+			t.Errorf("Got error: \"%v\", expected: \"%v\"", err, context.Canceled)
+		}
+	}
+}
+
+func TestPoolShutdownFullQueue(t *testing.T) {
+	started := make(chan struct{}, 1)
+	pool := concurrency.NewPool(concurrency.PoolOptions{Workers: 1, QueueSize: 1}, func(ctx context.Context, in int) (int, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	// Fill the worker and the queue, then block a third Submit on the full queue
+	_, _ = pool.Submit(0)
+	<-started
+	_, _ = pool.Submit(1)
+	blocked := make(chan error)
+	go func() {
+		_, err := pool.Submit(2)
+		blocked <- err
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	shutdown := make(chan error)
+	go func() { shutdown <- pool.Shutdown(ctx) }()
+
+	select {
+	case err := <-shutdown:
+		if err != context.DeadlineExceeded {
+			t.Errorf("Got error: \"%v\", expected: \"%v\"", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not honour its context whilst Submit was blocked")
+	}
+	if err := <-blocked; err != concurrency.ErrPoolClosed {
+		t.Errorf("Got error: \"%v\", expected: \"%v\"", err, concurrency.ErrPoolClosed)
+	}
+	<-pool.Done()
+}
+
+func TestPipelineCancel(t *testing.T) {
+	for _, unbounded := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := concurrency.NewPipeline(ctx, concurrency.PipelineOptions{Unbounded: unbounded})
+
+		values := make([]int, 1000)
+		for i := range values {
+			values[i] = i
+		}
+		doubled := concurrency.Map(p, concurrency.Source(p, values...), func(ctx context.Context, value int) (int, error) {
+			return value * 2, nil
+		})
+		batches := concurrency.Batch(p, doubled, 10, 0)
+
+		// Only read the first batch then cancel the pipeline
+		if batch := <-batches; len(batch) != 10 {
+			t.Errorf("unbounded = %t: got batch of %d, expected: %d", unbounded, len(batch), 10)
+		}
+		cancel()
+
+		waited := make(chan error)
+		go func() { waited <- p.Wait() }()
+		select {
+		case err := <-waited:
+			if err != context.Canceled {
+				t.Errorf("unbounded = %t: got error \"%v\", expected: \"%v\"", unbounded, err, context.Canceled)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("unbounded = %t: stages did not exit after cancellation", unbounded)
+		}
+	}
+}
+
+func TestPipelinePanic(t *testing.T) {
+	errs := make(chan error, 1)
+	p := concurrency.NewPipeline(context.Background(), concurrency.PipelineOptions{
+		OnError: func(err error) { errs <- err },
+	})
+	out := concurrency.Filter(p, concurrency.Source(p, 1, 2, 3), func(ctx context.Context, value int) (bool, error) {
+		panic("filter panicked")
+	})
+	for range out {
+	}
+
+	var panicErr *concurrency.PanicError
+	if err := p.Wait(); !errors.As(err, &panicErr) || panicErr.Value != "filter panicked" {
+		t.Errorf("Got error: \"%v\", expected a PanicError", err)
+	}
+	if err := <-errs; !errors.As(err, &panicErr) {
+		t.Errorf("OnError got error: \"%v\", expected a PanicError", err)
+	}
+}
+
+func TestPipelineInvalidStages(t *testing.T) {
+	p := concurrency.NewPipeline(context.Background(), concurrency.PipelineOptions{})
+	defer p.Cancel()
+	in := concurrency.Source(p, 1, 2, 3)
+	for name, create := range map[string]func(){
+		"Batch of 0":   func() { concurrency.Batch(p, in, 0, 0) },
+		"Batch of -1":  func() { concurrency.Batch(p, in, -1, time.Second) },
+		"FanOut to 0":  func() { concurrency.FanOut(p, in, 0) },
+		"FanOut to -1": func() { concurrency.FanOut(p, in, -1) },
+		"Tee to 0":     func() { concurrency.Tee(p, in, 0) },
+		"Tee to -1":    func() { concurrency.Tee(p, in, -1) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			create()
+		}()
+	}
+}
+
+func TestBatchTimeout(t *testing.T) {
+	p := concurrency.NewPipeline(context.Background(), concurrency.PipelineOptions{})
+	in := make(chan int)
+	batches := concurrency.Batch(p, in, 10, 20*time.Millisecond)
+
+	in <- 1
+	in <- 2
+	if batch := <-batches; !reflect.DeepEqual(batch, []int{1, 2}) {
+		t.Errorf("Got batch: %v, expected: %v", batch, []int{1, 2})
+	}
+	in <- 3
+	close(in)
+	if batch := <-batches; !reflect.DeepEqual(batch, []int{3}) {
+		t.Errorf("Got batch: %v, expected: %v", batch, []int{3})
+	}
+	if err := p.Wait(); err != nil {
+		t.Errorf("Got error: \"%v\", expected: nil", err)
+	}
+}
+
+func TestPriorityUnbounded(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	ctx, cancel := context.WithCancel(context.Background())
+	u := concurrency.NewPriorityUnbounded(ctx, func(a, b int) bool { return a > b }, concurrency.UnboundedOptions[int]{})
+
+	values := r.Perm(500)
+	for _, v := range values {
+		u.In() <- v
+	}
+
+	// Read the first few values then drain the rest
+	for expected := 499; expected > 489; expected-- {
+		if v := <-u.Out(); v != expected {
+			t.Errorf("Got: %d, expected: %d", v, expected)
+		}
+	}
+	cancel()
+
+	unread := u.Drain()
+	if len(unread) != 490 {
+		t.Fatalf("Got %d unread values, expected: %d", len(unread), 490)
+	}
+	if !sort.SliceIsSorted(unread, func(i, j int) bool { return unread[i] > unread[j] }) {
+		t.Errorf("Unread values are not in priority order: %v", unread)
+	}
+}
+
+func TestPriorityUnboundedDropOldest(t *testing.T) {
+	dropped := make([]int, 0)
+	opts := concurrency.UnboundedOptions[int]{
+		SoftCap:    5,
+		Overflow:   concurrency.OverflowDropOldest,
+		OnOverflow: func(item int, err error) { dropped = append(dropped, item) },
+	}
+	u := concurrency.NewPriorityUnbounded(context.Background(), func(a, b int) bool { return a > b }, opts)
+
+	// The lowest priority value is dropped to make room, even when the new value has a lower priority
+	for _, v := range []int{10, 20, 30, 40, 50, 5, 60, 1} {
+		u.In() <- v
+	}
+	close(u.In())
+
+	read := make([]int, 0)
+	for v := range u.Out() {
+		read = append(read, v)
+	}
+	if expected := []int{60, 50, 40, 30, 1}; !reflect.DeepEqual(read, expected) {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", read, expected)
+	}
+	if expected := []int{10, 5, 20}; !reflect.DeepEqual(dropped, expected) {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", dropped, expected)
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	clock := concurrency.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := concurrency.NewLimiterWithClock(2, 1, clock)
+	ctx := context.Background()
+
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("First Wait returned an error: %v", err)
+	}
+
+	waited := make(chan error)
+	go func() { waited <- limiter.Wait(ctx) }()
+	clock.BlockUntil(1)
+	select {
+	case err := <-waited:
+		t.Fatalf("Wait returned %v before the clock was advanced", err)
+	default:
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if err := <-waited; err != nil {
+		t.Errorf("Wait returned an error: %v", err)
+	}
+
+	// Cancelling the context should return the token
+	cctx, cancel := context.WithCancel(ctx)
+	go func() { waited <- limiter.Wait(cctx) }()
+	clock.BlockUntil(1)
+	cancel()
+	if err := <-waited; err != context.Canceled {
+		t.Errorf("Got error: \"%v\", expected: \"%v\"", err, context.Canceled)
+	}
+	if tokens := limiter.Tokens(); tokens != 0 {
+		t.Errorf("Got %f tokens, expected: %f", tokens, 0.0)
+	}
+
+	if err := concurrency.NewLimiterWithClock(1, 0, clock).Wait(ctx); err != concurrency.ErrLimitExceeded {
+		t.Errorf("Got error: \"%v\", expected: \"%v\"", err, concurrency.ErrLimitExceeded)
+	}
+	if !concurrency.NewLimiterWithClock(concurrency.Inf, 0, clock).Allow() {
+		t.Errorf("Infinite Limiter did not allow an event")
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	clock := concurrency.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	window := concurrency.NewSlidingWindowWithClock(2, time.Second, clock)
+	in, out := concurrency.InOut[int]()
+	for i := 0; i < 5; i++ {
+		in <- i
+	}
+	close(in)
+
+	limited := concurrency.RateLimited(context.Background(), out, window)
+	for expected := 0; expected < 5; expected++ {
+		// Two values are allowed every second
+		if expected > 0 && expected%2 == 0 {
+			clock.BlockUntil(1)
+			clock.Advance(time.Second)
+		}
+
+		if v := <-limited; v != expected {
+			t.Errorf("Got: %d, expected: %d", v, expected)
+		}
+		if now := clock.Now(); now.Second() != expected/2 {
+			t.Errorf("Got %d at %s, expected it at %ds", expected, now, expected/2)
+		}
+	}
+
+	if _, ok := <-limited; ok {
+		t.Errorf("RateLimited channel was not closed")
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	const limit = 3
+	var running, maxRunning int64
+	g, _ := concurrency.NewGroup(context.Background(), concurrency.GroupOptions{Limit: limit})
+	for i := 0; i < 20; i++ {
+		g.Go(func(ctx context.Context) error {
+			n := atomic.AddInt64(&running, 1)
+			for {
+				max := atomic.LoadInt64(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt64(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&running, -1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("Got error: \"%v\", expected: nil", err)
+	}
+	if maxRunning > limit {
+		t.Errorf("%d goroutines were running at once, expected at most: %d", maxRunning, limit)
+	}
+}
+
+func TestGroupCancelOnError(t *testing.T) {
+	errFailed := errors.New("failed")
+	g, ctx := concurrency.NewGroup(context.Background(), concurrency.GroupOptions{CancelOnError: true})
+	g.Go(func(ctx context.Context) error {
+		return errFailed
+	})
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	err := g.Wait()
+	var multi *concurrency.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("Got error: \"%v\", expected a MultiError with 2 errors", err)
+	}
+	if !errors.Is(err, errFailed) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Got error: \"%v\", expected it to contain \"%v\" and \"%v\"", err, errFailed, context.Canceled)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("Group context was not cancelled")
+	}
+
+	// TryGo should fail when the limit is reached
+	g, _ = concurrency.NewGroup(context.Background(), concurrency.GroupOptions{Limit: 1})
+	block := make(chan struct{})
+	if !g.TryGo(func(ctx context.Context) error { <-block; return nil }) {
+		t.Errorf("TryGo did not start the first goroutine")
+	}
+	if g.TryGo(func(ctx context.Context) error { return nil }) {
+		t.Errorf("TryGo started a goroutine past the limit")
+	}
+	close(block)
+	if err = g.Wait(); err != nil {
+		t.Errorf("Got error: \"%v\", expected: nil", err)
+	}
+}
+
+func TestEventsClose(t *testing.T) {
+	clock := concurrency.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	in := make(chan int)
+	debounced := concurrency.DebounceWithClock(in, time.Second, clock)
+	close(in)
+	if v, ok := <-debounced; ok {
+		t.Errorf("Debounce emitted %d after closing without a pending value", v)
+	}
+
+	in = make(chan int)
+	throttled := concurrency.ThrottleWithClock(in, time.Second, clock)
+	close(in)
+	if v, ok := <-throttled; ok {
+		t.Errorf("Throttle emitted %d after closing", v)
+	}
+
+	in = make(chan int)
+	coalesced := concurrency.CoalesceWithClock(in, func(value int) int { return value }, time.Second, clock)
+	close(in)
+	if v, ok := <-coalesced; ok {
+		t.Errorf("Coalesce emitted %v after closing without a pending window", v)
+	}
+}
+
+func TestDebounceBursts(t *testing.T) {
+	clock := concurrency.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	in := make(chan int)
+	out := concurrency.DebounceWithClock(in, time.Second, clock)
+
+	for burst := 0; burst < 3; burst++ {
+		for i := 0; i < 5; i++ {
+			in <- burst*10 + i
+			clock.BlockUntilTimer(clock.Now().Add(time.Second))
+			clock.Advance(999 * time.Millisecond)
+		}
+
+		select {
+		case v := <-out:
+			t.Fatalf("Debounce emitted %d before the burst had settled", v)
+		default:
+		}
+
+		clock.Advance(time.Millisecond)
+		if v := <-out; v != burst*10+4 {
+			t.Errorf("Got: %d, expected: %d", v, burst*10+4)
+		}
+	}
+	close(in)
+}
+
+func TestMemoizeErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	var calls int64
+	memo := concurrency.Memoize(func(key int) (int, error) {
+		atomic.AddInt64(&calls, 1)
+		if key%2 != 0 {
+			return 0, errOdd
+		}
+		return key / 2, nil
+	}, concurrency.MemoOptions{})
+
+	for i := 0; i < 3; i++ {
+		if val, err := memo.Get(4); val != 2 || err != nil {
+			t.Errorf("Got %d, %v, expected: 2, nil", val, err)
+		}
+		if _, err := memo.Get(3); err != errOdd {
+			t.Errorf("Got error: \"%v\", expected: \"%v\"", err, errOdd)
+		}
+	}
+
+	// Errors aren't cached so the odd key should be computed every time
+	if calls != 4 {
+		t.Errorf("Function was called %d times, expected: %d", calls, 4)
+	}
+	stats := memo.Stats()
+	if stats.Hits != 2 || stats.Misses != 4 || stats.Size != 1 {
+		t.Errorf("Got stats: %+v", stats)
+	}
+
+	memo.Forget(4)
+	if memo.Len() != 0 {
+		t.Errorf("Got Len: %d after Forget, expected: %d", memo.Len(), 0)
+	}
+}
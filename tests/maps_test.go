@@ -0,0 +1,466 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/andygello555/gotils/v2/maps"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentMap(t *testing.T) {
+	type key struct {
+		name string
+		id   int
+	}
+
+	for _, shards := range []int{0, 1, 7} {
+		cm := maps.NewConcurrentMap[key, int](shards)
+		expected := make(map[key]int)
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					k := key{strconv.Itoa(i % 10), i % 3}
+					cm.Compute(k, func(val int, loaded bool) (int, bool) { return val + 1, true })
+					cm.Load(k)
+					cm.Range(func(i int, key key, val int) bool { return i < 5 })
+				}
+			}(g)
+		}
+		for i := 0; i < 100; i++ {
+			expected[key{strconv.Itoa(i % 10), i % 3}] += 8
+		}
+		wg.Wait()
+
+		if snapshot := cm.Snapshot(); !reflect.DeepEqual(snapshot, expected) {
+			t.Errorf("shards = %d: got %v, expected: %v", shards, snapshot, expected)
+		}
+
+		cm.Filter(func(i int, key key, val int) bool { return key.id == 0 })
+		maps.Filter(expected, func(i int, key key, val int) bool { return key.id == 0 })
+		if snapshot := cm.Snapshot(); !reflect.DeepEqual(snapshot, expected) {
+			t.Errorf("shards = %d: got %v after Filter, expected: %v", shards, snapshot, expected)
+		}
+
+		if val, loaded := cm.LoadAndDelete(key{"0", 0}); !loaded || val != expected[key{"0", 0}] {
+			t.Errorf("shards = %d: got %d, %t from LoadAndDelete, expected: %d, true", shards, val, loaded, expected[key{"0", 0}])
+		}
+		if _, ok := cm.Load(key{"0", 0}); ok {
+			t.Errorf("shards = %d: key still exists after LoadAndDelete", shards)
+		}
+		if _, keep := cm.Compute(key{"3", 0}, func(val int, loaded bool) (int, bool) { return 0, false }); keep || cm.Len() != len(expected)-2 {
+			t.Errorf("shards = %d: Compute did not delete key, Len = %d, expected: %d", shards, cm.Len(), len(expected)-2)
+		}
+	}
+}
+
+func TestConcurrentMapBulk(t *testing.T) {
+	for _, shards := range []int{1, 7} {
+		cm := maps.NewConcurrentMap[string, int](shards)
+		// Union, Filter and Difference lock every shard, so a snapshot should only ever see none, the even, or all the keys
+		// in batch
+		batch := make(map[string]int)
+		for i := 0; i < 500; i++ {
+			batch["batch"+strconv.Itoa(i)] = i
+		}
+
+		var wg, reader sync.WaitGroup
+		done := make(chan struct{})
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					cm.Store("store"+strconv.Itoa(g), i)
+					cm.Compute("compute"+strconv.Itoa(i%10), func(val int, loaded bool) (int, bool) { return val + 1, true })
+				}
+			}(g)
+		}
+		reader.Add(1)
+		go func() {
+			defer reader.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				seen := 0
+				for key := range cm.Snapshot() {
+					if strings.HasPrefix(key, "batch") {
+						seen++
+					}
+				}
+				if seen != 0 && seen != len(batch)/2 && seen != len(batch) {
+					t.Errorf("shards = %d: got %d batch keys in snapshot, expected: 0, %d or %d", shards, seen, len(batch)/2, len(batch))
+				}
+			}
+		}()
+
+		for i := 0; i < 100; i++ {
+			cm.Union(batch)
+			cm.Filter(func(i int, key string, val int) bool { return !strings.HasPrefix(key, "batch") || val%2 == 0 })
+			cm.Union(batch)
+			cm.Difference(batch)
+		}
+		wg.Wait()
+		close(done)
+		reader.Wait()
+
+		expected := map[string]int{"store0": 199, "store1": 199, "store2": 199, "store3": 199}
+		for i := 0; i < 10; i++ {
+			expected["compute"+strconv.Itoa(i)] = 80
+		}
+		if snapshot := cm.Snapshot(); !reflect.DeepEqual(snapshot, expected) {
+			t.Errorf("shards = %d: got %v, expected: %v", shards, snapshot, expected)
+		}
+	}
+}
+
+func TestConcurrentMapFunc(t *testing.T) {
+	calls := 0
+	cm := maps.NewConcurrentMapFunc[int, int](4, func(key int) uint64 {
+		calls++
+		return uint64(key)
+	})
+	for i := 0; i < 10; i++ {
+		cm.Store(i, i*i)
+	}
+	for i := 0; i < 10; i++ {
+		if val, ok := cm.Load(i); !ok || val != i*i {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", val, ok, i*i, true)
+		}
+	}
+	if calls != 20 {
+		t.Errorf("Got: \"%v\", expected: \"%v\" calls to the hash function", calls, 20)
+	}
+}
+
+type badCloner struct{ value int }
+
+func (bc badCloner) Clone() any { return bc.value }
+
+type valueCloner struct {
+	Items  []int
+	Cloned bool
+}
+
+func (vc valueCloner) Clone() any {
+	return valueCloner{Items: append([]int{}, vc.Items...), Cloned: true}
+}
+
+type pointerCloner struct {
+	Items  []int
+	Cloned bool
+}
+
+func (pc *pointerCloner) Clone() any {
+	return &pointerCloner{Items: append([]int{}, pc.Items...), Cloned: true}
+}
+
+func TestDeepCopy(t *testing.T) {
+	type inner struct {
+		Values []int
+		hidden []int
+	}
+	type outer struct {
+		Inner  inner
+		Ptr    *inner
+		Any    any
+		Array  [2][]int
+		Map    map[int][]string
+		Func   func() int
+		hidden *inner
+	}
+
+	shared := []int{1, 2}
+	for i, test := range []struct {
+		value    any
+		opts     maps.DeepCopyOptions
+		expected any
+		mutate   func(cp any)
+	}{
+		{
+			value:    map[string]any{"a": []any{1, map[string]any{"b": []any{2}}}},
+			expected: map[string]any{"a": []any{1, map[string]any{"b": []any{2}}}},
+			mutate: func(cp any) {
+				cp.(map[string]any)["a"].([]any)[1].(map[string]any)["b"].([]any)[0] = 3
+			},
+		},
+		{
+			value: outer{
+				Inner: inner{Values: []int{1}, hidden: shared},
+				Ptr:   &inner{Values: []int{2}},
+				Any:   []any{"a"},
+				Array: [2][]int{{3}, {4}},
+				Map:   map[int][]string{1: {"b"}},
+			},
+			expected: outer{
+				Inner: inner{Values: []int{1}, hidden: shared},
+				Ptr:   &inner{Values: []int{2}},
+				Any:   []any{"a"},
+				Array: [2][]int{{3}, {4}},
+				Map:   map[int][]string{1: {"b"}},
+			},
+			mutate: func(cp any) {
+				o := cp.(outer)
+				o.Inner.Values[0] = 10
+				o.Ptr.Values[0] = 20
+				o.Any.([]any)[0] = "z"
+				o.Array[1][0] = 40
+				o.Map[1][0] = "y"
+			},
+		},
+		{
+			value:    outer{Inner: inner{hidden: []int{1}}, hidden: &inner{}},
+			opts:     maps.DeepCopyOptions{Unexported: maps.UnexportedZero},
+			expected: outer{},
+		},
+		{
+			value:    nil,
+			expected: nil,
+		},
+		{
+			value:    []any{nil, (*int)(nil), map[string]int(nil), []int(nil)},
+			expected: []any{nil, (*int)(nil), map[string]int(nil), []int(nil)},
+		},
+	} {
+		original := fmt.Sprintf("%#v", test.value)
+		cp := maps.DeepCopyWithOptions(test.value, test.opts)
+		if !reflect.DeepEqual(cp, test.expected) {
+			t.Errorf("%d: Got: \"%#v\", expected: \"%#v\"", i, cp, test.expected)
+		}
+		if test.mutate != nil {
+			test.mutate(cp)
+			if after := fmt.Sprintf("%#v", test.value); after != original {
+				t.Errorf("%d: Got: \"%v\", expected: \"%v\"", i, after, original)
+			}
+		}
+	}
+
+	// Slices that are shared within the original should also be shared within the copy
+	var decoded []any
+	_ = json.Unmarshal([]byte(`[[1, 2], {"a": [3]}]`), &decoded)
+	aliased := []any{decoded, decoded}
+	cp := maps.DeepCopy(aliased)
+	cp[0].([]any)[0].([]any)[0] = "changed"
+	if decoded[0].([]any)[0] != float64(1) || cp[1].([]any)[0].([]any)[0] != "changed" {
+		t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", decoded[0], cp[1], []any{1, 2}, []any{"changed", 2})
+	}
+
+	// A slice that contains itself
+	cyclic := make([]any, 1)
+	cyclic[0] = cyclic
+	cyclicCopy := maps.DeepCopy(cyclic)
+	if reflect.ValueOf(cyclicCopy).Pointer() == reflect.ValueOf(cyclic).Pointer() ||
+		reflect.ValueOf(cyclicCopy[0]).Pointer() != reflect.ValueOf(cyclicCopy).Pointer() {
+		t.Errorf("Got: \"%p\", expected: \"%p\"", cyclicCopy[0], cyclicCopy)
+	}
+
+	// A pointer to a type with a value receiver Clone method should be copied, and the pointed to value cloned
+	original := &valueCloner{Items: []int{1}}
+	for _, cp := range []*valueCloner{maps.DeepCopy(original), maps.DeepCopy([]any{original})[0].(*valueCloner)} {
+		if cp == original || !cp.Cloned || !reflect.DeepEqual(cp.Items, original.Items) {
+			t.Errorf("Got: \"%p, %+v\", expected: \"not %p, %+v\"", cp, cp, original, valueCloner{Items: []int{1}, Cloned: true})
+		}
+	}
+	if cp := maps.DeepCopy(valueCloner{Items: []int{1}}); !cp.Cloned {
+		t.Errorf("Got: \"%+v\", expected: \"%+v\"", cp, valueCloner{Items: []int{1}, Cloned: true})
+	}
+
+	// A pointer receiver Clone method should be used for the pointer, but not for the value
+	if cp := maps.DeepCopy(&pointerCloner{Items: []int{1}}); !cp.Cloned {
+		t.Errorf("Got: \"%+v\", expected: \"%+v\"", cp, pointerCloner{Items: []int{1}, Cloned: true})
+	}
+	if cp := maps.DeepCopy(pointerCloner{Items: []int{1}}); cp.Cloned {
+		t.Errorf("Got: \"%+v\", expected: \"%+v\"", cp, pointerCloner{Items: []int{1}})
+	}
+
+	// A Clone method that returns a different type should panic
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", r, "panic")
+			}
+		}()
+		maps.DeepCopy(badCloner{1})
+	}()
+}
+
+func TestDiff(t *testing.T) {
+	type address struct {
+		City     string `json:"city"`
+		Postcode string `json:"-"`
+		Lines    []string
+		private  int
+	}
+	type node struct {
+		Value int
+		Next  *node
+	}
+	cycleA := &node{Value: 1}
+	cycleA.Next = cycleA
+	cycleB := &node{Value: 2}
+	cycleB.Next = cycleB
+	selfA := map[string]any{"value": 1}
+	selfA["self"] = selfA
+	selfB := map[string]any{"value": 2}
+	selfB["self"] = selfB
+	type record struct {
+		Name    string
+		Created time.Time
+		Balance *big.Int
+	}
+	created := time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)
+	sharedA, sharedB := &node{Value: 1}, &node{Value: 2}
+	cyclicA := []any{nil}
+	cyclicA[0] = cyclicA
+	cyclicB := []any{nil}
+	cyclicB[0] = cyclicB
+	cyclicC := []any{1, nil}
+	cyclicC[1] = cyclicC
+	cyclicD := []any{2, nil}
+	cyclicD[1] = cyclicD
+
+	for i, test := range []struct {
+		a, b     any
+		opts     maps.DiffOptions
+		expected []maps.Change
+	}{
+		{
+			a:        map[string]any{"a": 1, "b": []any{1, 2}},
+			b:        map[string]any{"a": 1.0, "b": []any{1, 2}},
+			expected: nil,
+		},
+		{
+			a:        1,
+			b:        "1",
+			expected: []maps.Change{{Type: maps.ChangeModified, Path: "", Old: 1, New: "1"}},
+		},
+		{
+			a: map[string]any{"a/b": 1, "c~d": map[string]any{"e": true}},
+			b: map[string]any{"a/b": 2, "c~d": map[string]any{"e": false}},
+			expected: []maps.Change{
+				{Type: maps.ChangeModified, Path: "/a~1b", Old: 1, New: 2},
+				{Type: maps.ChangeModified, Path: "/c~0d/e", Old: true, New: false},
+			},
+		},
+		{
+			a:        map[string][]int{"a": nil},
+			b:        map[string][]int{"a": {}, "b": nil},
+			expected: []maps.Change{{Type: maps.ChangeAdded, Path: "/b", New: []int(nil)}},
+		},
+		{
+			a:        map[int]string{1: "a", 10: "b", 2: "c"},
+			b:        map[int]string{1: "a", 2: "d"},
+			expected: []maps.Change{{Type: maps.ChangeRemoved, Path: "/10", Old: "b"}, {Type: maps.ChangeModified, Path: "/2", Old: "c", New: "d"}},
+		},
+		{
+			a: &address{City: "Leeds", Postcode: "LS1", Lines: []string{"1 Road"}, private: 1},
+			b: &address{City: "York", Postcode: "YO1", Lines: []string{"1 Road", "Flat 2"}, private: 2},
+			expected: []maps.Change{
+				{Type: maps.ChangeModified, Path: "/city", Old: "Leeds", New: "York"},
+				{Type: maps.ChangeAdded, Path: "/Lines/1", New: "Flat 2"},
+			},
+		},
+		{
+			a:        []any{1, 1, 2, 3},
+			b:        []any{3, 1, 4, 2.0},
+			opts:     maps.DiffOptions{IgnoreOrder: true},
+			expected: []maps.Change{{Type: maps.ChangeRemoved, Path: "/1", Old: 1}, {Type: maps.ChangeAdded, Path: "/2", New: 4}},
+		},
+		{
+			a:        []float64{1, 2, math.NaN()},
+			b:        []float64{1.05, 2.2, math.NaN()},
+			opts:     maps.DiffOptions{FloatTolerance: 0.1},
+			expected: []maps.Change{{Type: maps.ChangeModified, Path: "/1", Old: 2.0, New: 2.2}},
+		},
+		{
+			a:        map[string]any{"a": map[string]any{"b": 1, "c": 2}, "d": []any{map[string]any{"e": 1}}},
+			b:        map[string]any{"a": map[string]any{"b": 2, "c": 3}, "d": []any{map[string]any{"e": 2}}},
+			opts:     maps.DiffOptions{IgnorePaths: []string{"/a/b", "/d/*/e"}},
+			expected: []maps.Change{{Type: maps.ChangeModified, Path: "/a/c", Old: 2, New: 3}},
+		},
+		{
+			a:        map[string]any{"a": 1},
+			b:        map[string]any{"b": 2},
+			opts:     maps.DiffOptions{IgnorePaths: []string{""}},
+			expected: nil,
+		},
+		{
+			a:        cycleA,
+			b:        cycleB,
+			expected: []maps.Change{{Type: maps.ChangeModified, Path: "/Value", Old: 1, New: 2}},
+		},
+		{
+			a:        selfA,
+			b:        selfB,
+			expected: []maps.Change{{Type: maps.ChangeModified, Path: "/value", Old: 1, New: 2}},
+		},
+		{
+			a: map[any]any{1: "a", "1": "b", 2: "c"},
+			b: map[any]any{1: "x", "1": "y", 2: "c", "3": "d"},
+			expected: []maps.Change{
+				{Type: maps.ChangeAdded, Path: "/3", New: "d"},
+				{Type: maps.ChangeModified, Path: "/int(1)", Old: "a", New: "x"},
+				{Type: maps.ChangeModified, Path: "/string(1)", Old: "b", New: "y"},
+			},
+		},
+		{
+			a:        map[any]int{1: 1},
+			b:        map[any]int{"1": 1},
+			expected: []maps.Change{{Type: maps.ChangeRemoved, Path: "/int(1)", Old: 1}, {Type: maps.ChangeAdded, Path: "/string(1)", New: 1}},
+		},
+		{
+			a:        cyclicA,
+			b:        cyclicB,
+			opts:     maps.DiffOptions{IgnoreOrder: true},
+			expected: nil,
+		},
+		{
+			a:        cyclicC,
+			b:        cyclicD,
+			opts:     maps.DiffOptions{IgnoreOrder: true},
+			expected: []maps.Change{{Type: maps.ChangeRemoved, Path: "/0", Old: 1}, {Type: maps.ChangeAdded, Path: "/0", New: 2}},
+		},
+		{
+			a: map[string]any{"x": sharedA, "y": sharedA},
+			b: map[string]any{"x": sharedB, "y": sharedB},
+			expected: []maps.Change{
+				{Type: maps.ChangeModified, Path: "/x/Value", Old: 1, New: 2},
+				{Type: maps.ChangeModified, Path: "/y/Value", Old: 1, New: 2},
+			},
+		},
+		{
+			a: record{Name: "a", Created: created, Balance: big.NewInt(1)},
+			b: record{Name: "a", Created: created.Add(time.Hour), Balance: big.NewInt(2)},
+			expected: []maps.Change{
+				{Type: maps.ChangeModified, Path: "/Created", Old: created, New: created.Add(time.Hour)},
+				{Type: maps.ChangeModified, Path: "/Balance", Old: *big.NewInt(1), New: *big.NewInt(2)},
+			},
+		},
+		{
+			a:        record{Created: created, Balance: big.NewInt(1)},
+			b:        record{Created: created.In(time.FixedZone("UTC+1", 3600)), Balance: big.NewInt(1)},
+			expected: nil,
+		},
+		{
+			a:        map[string]any{"a": nil, "b": (*int)(nil)},
+			b:        map[string]any{"a": map[string]any{}, "b": nil},
+			expected: []maps.Change{{Type: maps.ChangeModified, Path: "/a", New: map[string]any{}}},
+		},
+	} {
+		if changes := maps.Diff(test.a, test.b, test.opts); !reflect.DeepEqual(changes, test.expected) {
+			t.Errorf("%d: Got: \"%v\", expected: \"%v\"", i, changes, test.expected)
+		}
+	}
+}
@@ -1056,3 +1056,43 @@ func TestOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestParallel(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	isEven := func(idx int, value int, arr []int) bool { return value%2 == 0 }
+	isPositive := func(idx int, value int, arr []int) bool { return value > 0 }
+	isOver90 := func(idx int, value int, arr []int) bool { return value > 90 }
+	double := func(idx int, value int, arr []int) string { return fmt.Sprintf("%d:%d", idx, value*2) }
+
+	for _, length := range []int{0, 1, 7, 100, 1000} {
+		array := make([]int, length)
+		for i := range array {
+			array[i] = r.Intn(100)
+		}
+
+		for _, workers := range []int{-1, 0, 1, 3, 16, 2000} {
+			if expected, actual := slices.Comprehension(array, double), slices.ParallelComprehension(array, workers, double); !reflect.DeepEqual(expected, actual) {
+				t.Errorf("ParallelComprehension(len = %d, workers = %d): Got %v, expected %v", length, workers, actual, expected)
+			}
+
+			if expected, actual := slices.Filter(array, isEven), slices.ParallelFilter(array, workers, isEven); !reflect.DeepEqual(expected, actual) {
+				t.Errorf("ParallelFilter(len = %d, workers = %d): Got %v, expected %v", length, workers, actual, expected)
+			}
+
+			for _, funcs := range [][]func(idx int, value int, arr []int) bool{
+				{},
+				{isEven},
+				{isOver90},
+				{isPositive, isEven},
+			} {
+				if expected, actual := slices.Any(array, funcs...), slices.ParallelAny(array, workers, funcs...); expected != actual {
+					t.Errorf("ParallelAny(len = %d, workers = %d, funcs = %d): Got %t, expected %t", length, workers, len(funcs), actual, expected)
+				}
+
+				if expected, actual := slices.All(array, funcs...), slices.ParallelAll(array, workers, funcs...); expected != actual {
+					t.Errorf("ParallelAll(len = %d, workers = %d, funcs = %d): Got %t, expected %t", length, workers, len(funcs), actual, expected)
+				}
+			}
+		}
+	}
+}
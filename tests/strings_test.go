@@ -81,6 +81,18 @@ func TestReplaceCharIndexRange(t *testing.T) {
 			[]string{},
 			"Hello",
 		},
+		{
+			"Hello world",
+			[][]int{{6, 11}, {0, 5}, {3, 8}, {0, 5}, {6, 11}},
+			[]string{"Goodbye", "everyone"},
+			"Goodbye everyone",
+		},
+		{
+			"Hello world",
+			[][]int{{6, 11}, {0, 5}, {5, 5}, {4, 7}, {8, 3}, {20, 25}},
+			[]string{"Howdy", "y'all"},
+			"Howdy y'all",
+		},
 	} {
 		newString := strings.ReplaceCharIndexRange(test.old, test.indices, test.new...)
 		if newString != test.expectedOutput {
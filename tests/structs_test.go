@@ -0,0 +1,1258 @@
+package tests
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"github.com/andygello555/gotils/v2/maps"
+	"github.com/andygello555/gotils/v2/numbers"
+	"github.com/andygello555/gotils/v2/structs"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lruModel is a simple O(n) LRU which is used to check the behaviour of structs.LRU.
+type lruModel struct {
+	capacity int
+	keys     []int
+	vals     map[int]int
+}
+
+func (m *lruModel) touch(key int) {
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	m.keys = append([]int{key}, m.keys...)
+}
+
+func (m *lruModel) set(key, val int) (evicted int, ok bool) {
+	if _, exists := m.vals[key]; !exists && len(m.keys) == m.capacity {
+		evicted, ok = m.keys[len(m.keys)-1], true
+		m.keys = m.keys[:len(m.keys)-1]
+		delete(m.vals, evicted)
+	}
+	m.vals[key] = val
+	m.touch(key)
+	return
+}
+
+func TestLRU(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for _, capacity := range []int{1, 2, 5, 16} {
+		var evicted []int
+		cache := structs.NewLRU[int, int](capacity, structs.CacheOptions[int, int]{
+			OnEvict: func(key int, val int, reason structs.EvictReason) { evicted = append(evicted, key) },
+		})
+		model := &lruModel{capacity: capacity, vals: make(map[int]int)}
+		var expectedEvicted []int
+
+		for i := 0; i < 1000; i++ {
+			key := r.Intn(capacity * 2)
+			switch r.Intn(4) {
+			case 0, 1:
+				cache.Set(key, i)
+				if k, ok := model.set(key, i); ok {
+					expectedEvicted = append(expectedEvicted, k)
+				}
+			case 2:
+				val, ok := cache.Get(key)
+				expectedVal, expectedOk := model.vals[key]
+				if expectedOk {
+					model.touch(key)
+				}
+				if val != expectedVal || ok != expectedOk {
+					t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", val, ok, expectedVal, expectedOk)
+				}
+			case 3:
+				if _, ok := model.vals[key]; ok != cache.Delete(key) {
+					t.Errorf("Got: \"%v\", expected: \"%v\"", !ok, ok)
+				} else if ok {
+					delete(model.vals, key)
+					for j, k := range model.keys {
+						if k == key {
+							model.keys = append(model.keys[:j], model.keys[j+1:]...)
+							break
+						}
+					}
+				}
+			}
+		}
+
+		if keys := cache.Keys(); len(keys)+len(model.keys) > 0 && !reflect.DeepEqual(keys, model.keys) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", keys, model.keys)
+		}
+		if !reflect.DeepEqual(cache.ToMap(), model.vals) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", cache.ToMap(), model.vals)
+		}
+		if !reflect.DeepEqual(evicted, expectedEvicted) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", evicted, expectedEvicted)
+		}
+	}
+}
+
+func TestLFU(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	type evictedEntry struct {
+		key    string
+		reason structs.EvictReason
+	}
+	var evicted []evictedEntry
+	cache := structs.NewLFU[string, int](3, structs.CacheOptions[string, int]{
+		OnEvict: func(key string, val int, reason structs.EvictReason) {
+			evicted = append(evicted, evictedEntry{key, reason})
+		},
+		Now: func() time.Time { return now },
+	})
+
+	for _, test := range []struct {
+		op      func()
+		keys    []string
+		evicted []evictedEntry
+		freqs   map[string]int
+	}{
+		{
+			op: func() {
+				cache.Set("a", 1)
+				cache.Set("b", 2)
+				cache.Set("c", 3)
+				cache.Get("a")
+				cache.Get("b")
+			},
+			keys:  []string{"b", "a", "c"},
+			freqs: map[string]int{"a": 2, "b": 2, "c": 1},
+		},
+		{
+			// All entries with the minimum frequency are deleted, so the next eviction must find the new minimum
+			op: func() {
+				cache.Delete("c")
+				cache.Set("d", 4)
+				cache.Get("d")
+				cache.Get("d")
+				cache.Set("e", 5)
+			},
+			keys:    []string{"d", "b", "e"},
+			evicted: []evictedEntry{{"a", structs.EvictCapacity}},
+			freqs:   map[string]int{"a": 0, "b": 2, "d": 3, "e": 1},
+		},
+		{
+			// Expired entries that are evicted to make room are given EvictExpired
+			op: func() {
+				cache.SetWithTTL("a", 1, time.Minute)
+				now = now.Add(time.Minute)
+				cache.Get("d")
+				cache.Set("f", 6)
+			},
+			keys: []string{"d", "b", "f"},
+			evicted: []evictedEntry{
+				{"a", structs.EvictCapacity},
+				{"e", structs.EvictCapacity},
+				{"a", structs.EvictExpired},
+			},
+			freqs: map[string]int{"a": 0, "b": 2, "d": 4, "f": 1},
+		},
+	} {
+		test.op()
+		if keys := cache.Keys(); !reflect.DeepEqual(keys, test.keys) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", keys, test.keys)
+		}
+		if !reflect.DeepEqual(evicted, test.evicted) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", evicted, test.evicted)
+		}
+		for key, freq := range test.freqs {
+			if f := cache.Frequency(key); f != freq {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", f, freq)
+			}
+		}
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	type value struct {
+		priority int
+		seq      int
+	}
+	r := rand.New(rand.NewSource(42))
+	for _, max := range []bool{false, true} {
+		less := func(a, b value) bool { return a.priority < b.priority }
+		pq := structs.NewPriorityQueue(less)
+		if max {
+			pq = structs.NewMaxPriorityQueue(less)
+		}
+
+		items := make([]*structs.PriorityQueueItem[value], 0)
+		for i := 0; i < 500; i++ {
+			items = append(items, pq.Push(value{r.Intn(50), i}))
+		}
+
+		// Update, fix, and remove random items
+		removed := make(map[int]struct{})
+		for i := 0; i < 200; i++ {
+			item := items[r.Intn(len(items))]
+			switch r.Intn(3) {
+			case 0:
+				pq.Update(item, value{r.Intn(50), item.Value.seq})
+			case 1:
+				item.Value.priority = r.Intn(50)
+				pq.Fix(item)
+			case 2:
+				_, seen := removed[item.Value.seq]
+				if _, ok := pq.Remove(item); ok == seen {
+					t.Errorf("Got: \"%v\", expected: \"%v\"", ok, !seen)
+				}
+				removed[item.Value.seq] = struct{}{}
+			}
+		}
+
+		expected := make([]value, 0)
+		for _, item := range items {
+			if item.Queued() {
+				expected = append(expected, item.Value)
+			}
+		}
+		sort.SliceStable(expected, func(i, j int) bool {
+			if max {
+				return expected[i].priority > expected[j].priority
+			}
+			return expected[i].priority < expected[j].priority
+		})
+
+		if pq.Len() != len(expected) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", pq.Len(), len(expected))
+		}
+		if peek, _ := pq.Peek(); len(expected) > 0 && peek.priority != expected[0].priority {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", peek, expected[0])
+		}
+		// Values with equal priority should be drained in the order that they were pushed
+		if drained := pq.Drain(); !reflect.DeepEqual(drained, expected) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", drained, expected)
+		}
+		if _, ok := pq.Pop(); ok {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", ok, false)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	type point struct{ X, Y int }
+	for _, test := range []struct {
+		a, b                                  structs.Set[point]
+		union, intersection, difference, symm []point
+		subset                                bool
+		json                                  string
+	}{
+		{
+			a:            structs.NewSet(point{2, 1}, point{1, 2}),
+			b:            structs.NewSet(point{1, 2}, point{3, 3}),
+			union:        []point{{1, 2}, {2, 1}, {3, 3}},
+			intersection: []point{{1, 2}},
+			difference:   []point{{2, 1}},
+			symm:         []point{{2, 1}, {3, 3}},
+			json:         `[{"X":1,"Y":2},{"X":2,"Y":1}]`,
+		},
+		{
+			a:            structs.NewSet[point](),
+			b:            structs.NewSet(point{1, 2}),
+			union:        []point{{1, 2}},
+			intersection: []point{},
+			difference:   []point{},
+			symm:         []point{{1, 2}},
+			subset:       true,
+			json:         `[]`,
+		},
+		{
+			a:            structs.NewSet(point{1, 2}, point{0, 0}),
+			b:            structs.NewSet(point{0, 0}, point{1, 2}, point{5, 5}),
+			union:        []point{{0, 0}, {1, 2}, {5, 5}},
+			intersection: []point{{0, 0}, {1, 2}},
+			difference:   []point{},
+			symm:         []point{{5, 5}},
+			subset:       true,
+			json:         `[{"X":0,"Y":0},{"X":1,"Y":2}]`,
+		},
+	} {
+		for _, check := range []struct {
+			got      structs.Set[point]
+			expected []point
+		}{
+			{test.a.UnionNew(test.b), test.union},
+			{test.a.IntersectionNew(test.b), test.intersection},
+			{test.a.DifferenceNew(test.b), test.difference},
+			{test.a.SymmetricDifferenceNew(test.b), test.symm},
+		} {
+			if !check.got.Equal(structs.NewSet(check.expected...)) {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", check.got, check.expected)
+			}
+		}
+
+		if subset := test.a.IsSubset(test.b); subset != test.subset {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", subset, test.subset)
+		}
+
+		data, err := json.Marshal(test.a)
+		if err != nil {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", err, nil)
+		} else if string(data) != test.json {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", string(data), test.json)
+		}
+
+		var s structs.Set[point]
+		if err = json.Unmarshal(data, &s); err != nil {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", err, nil)
+		} else if !s.Equal(test.a) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", s, test.a)
+		}
+	}
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		keys     []int
+		expected string
+		err      bool
+	}{
+		{`{"3": "c", "1": "a", "2": "b"}`, []int{3, 1, 2}, `{"3":"c","1":"a","2":"b"}`, false},
+		{`{"1": "a", "2": "b", "1": "c"}`, []int{1, 2}, `{"1":"c","2":"b"}`, false},
+		{`{}`, []int{}, `{}`, false},
+		{`{"a": "b"}`, nil, "", true},
+		{`["a"]`, nil, "", true},
+		{`{"1": 2}`, nil, "", true},
+	} {
+		om := structs.NewOrderedMap[int, string]()
+		om.Set(100, "should be replaced")
+		err := json.Unmarshal([]byte(test.input), om)
+		if (err != nil) != test.err {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", err, test.err)
+		}
+		if test.err {
+			continue
+		}
+
+		if keys := om.Keys(); !reflect.DeepEqual(keys, test.keys) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", keys, test.keys)
+		}
+		if data, err := json.Marshal(om); err != nil {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", err, nil)
+		} else if string(data) != test.expected {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", string(data), test.expected)
+		}
+	}
+}
+
+func TestOrderedMapCopy(t *testing.T) {
+	om := structs.NewOrderedMap[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+
+	// A copy shares its contents with the original, and both stay usable
+	cp := *om
+	cp.Set("c", 3)
+	om.MoveToFront("c")
+	for _, m := range []*structs.OrderedMap[string, int]{om, &cp} {
+		if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"c", "a", "b"}) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", keys, []string{"c", "a", "b"})
+		}
+	}
+}
+
+func TestDeque(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for _, minCapacity := range []int{0, 1, 3} {
+		d := structs.NewDeque[int](minCapacity)
+		model := make([]int, 0)
+		for i := 0; i < 2000; i++ {
+			// Bias towards pushing in the first half and popping in the second so that the Deque grows and shrinks
+			push := r.Intn(10) < 7
+			if i >= 1000 {
+				push = !push
+			}
+
+			switch front := r.Intn(2) == 0; {
+			case push && front:
+				d.PushFront(i)
+				model = append([]int{i}, model...)
+			case push:
+				d.PushBack(i)
+				model = append(model, i)
+			default:
+				var v int
+				var ok bool
+				expected, expectedOk := 0, len(model) > 0
+				if front {
+					v, ok = d.PopFront()
+					if expectedOk {
+						expected, model = model[0], model[1:]
+					}
+				} else {
+					v, ok = d.PopBack()
+					if expectedOk {
+						expected, model = model[len(model)-1], model[:len(model)-1]
+					}
+				}
+				if v != expected || ok != expectedOk {
+					t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", v, ok, expected, expectedOk)
+				}
+			}
+
+			if d.Len() != len(model) {
+				t.Fatalf("Got: \"%v\", expected: \"%v\"", d.Len(), len(model))
+			}
+			if d.Len() > 0 && (d.At(0) != model[0] || d.At(d.Len()-1) != model[len(model)-1]) {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", d.Values(), model)
+			}
+		}
+
+		if values := d.Values(); !reflect.DeepEqual(values, model) && len(model) > 0 {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", values, model)
+		}
+		// Clearing shrinks the Deque back down to its minimum capacity
+		d.Clear()
+		expectedCap := minCapacity
+		if expectedCap <= 0 {
+			expectedCap = 16
+		}
+		if d.Len() != 0 || d.Cap() != expectedCap {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", d.Len(), d.Cap(), 0, expectedCap)
+		}
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	for _, test := range []struct {
+		capacity  int
+		overwrite bool
+		ops       func(rb *structs.RingBuffer[int]) []error
+		errs      []error
+		values    []int
+	}{
+		{
+			capacity: 3,
+			ops: func(rb *structs.RingBuffer[int]) []error {
+				return []error{rb.PushBack(1), rb.PushBack(2), rb.PushFront(0), rb.PushBack(3), rb.PushFront(-1)}
+			},
+			errs:   []error{nil, nil, nil, structs.ErrRingBufferFull, structs.ErrRingBufferFull},
+			values: []int{0, 1, 2},
+		},
+		{
+			capacity:  3,
+			overwrite: true,
+			ops: func(rb *structs.RingBuffer[int]) []error {
+				return []error{rb.PushBack(1), rb.PushBack(2), rb.PushBack(3), rb.PushBack(4), rb.PushFront(0)}
+			},
+			errs:   []error{nil, nil, nil, nil, nil},
+			values: []int{0, 2, 3},
+		},
+		{
+			capacity:  4,
+			overwrite: true,
+			ops: func(rb *structs.RingBuffer[int]) []error {
+				errs := make([]error, 0)
+				for i := 0; i < 6; i++ {
+					errs = append(errs, rb.PushBack(i))
+				}
+				// Shrinking drops values from the front
+				rb.Resize(2)
+				errs = append(errs, rb.PushBack(6))
+				rb.Resize(3)
+				return append(errs, rb.PushBack(7))
+			},
+			errs:   []error{nil, nil, nil, nil, nil, nil, nil, nil},
+			values: []int{5, 6, 7},
+		},
+	} {
+		rb := structs.NewRingBuffer[int](test.capacity, test.overwrite)
+		if errs := test.ops(rb); !reflect.DeepEqual(errs, test.errs) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", errs, test.errs)
+		}
+		if values := rb.Values(); !reflect.DeepEqual(values, test.values) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", values, test.values)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	var l structs.List[string]
+	a := l.PushBack("a")
+	c := l.PushBack("c")
+	b := l.InsertBefore("b", c)
+	l.PushFront("z")
+	for _, test := range []struct {
+		op       func()
+		expected []string
+	}{
+		{func() {}, []string{"z", "a", "b", "c"}},
+		{func() { l.MoveAfter(a, c) }, []string{"z", "b", "c", "a"}},
+		{func() { l.MoveBefore(a, b) }, []string{"z", "a", "b", "c"}},
+		{func() { l.MoveToBack(l.Front()) }, []string{"a", "b", "c", "z"}},
+		{func() { l.Remove(b) }, []string{"a", "c", "z"}},
+		// Operations using Element(s) that have been removed, or are from other Lists, do nothing
+		{func() { l.Remove(b); l.MoveToFront(b); l.InsertAfter("x", b) }, []string{"a", "c", "z"}},
+		{func() { l.MoveToFront(structs.NewList("other").Front()) }, []string{"a", "c", "z"}},
+	} {
+		test.op()
+		if values := l.Values(); !reflect.DeepEqual(values, test.expected) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", values, test.expected)
+		}
+		if l.Len() != len(test.expected) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", l.Len(), len(test.expected))
+		}
+	}
+}
+
+func TestTreeMap(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	tm := structs.NewTreeMap[int, int]()
+	model := make(map[int]int)
+	for i := 0; i < 5000; i++ {
+		key := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, expected := model[key]
+			if deleted := tm.Delete(key); deleted != expected {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", deleted, expected)
+			}
+			delete(model, key)
+		} else {
+			_, exists := model[key]
+			if added := tm.Set(key, i); added == exists {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", added, !exists)
+			}
+			model[key] = i
+		}
+	}
+
+	keys := maps.OrderedKeys(model)
+	if tm.Len() != len(keys) {
+		t.Fatalf("Got: \"%v\", expected: \"%v\"", tm.Len(), len(keys))
+	}
+	if got := tm.Keys(); !reflect.DeepEqual(got, keys) {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", got, keys)
+	}
+
+	for i, key := range keys {
+		if val, ok := tm.Get(key); !ok || val != model[key] {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", val, ok, model[key], true)
+		}
+		if rank := tm.Rank(key); rank != i {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", rank, i)
+		}
+		if selected, _, ok := tm.Select(i); !ok || selected != key {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", selected, ok, key, true)
+		}
+	}
+
+	for probe := -1; probe <= 501; probe++ {
+		i := sort.SearchInts(keys, probe)
+		expectedCeiling, expectedCeilingOk := 0, i < len(keys)
+		if expectedCeilingOk {
+			expectedCeiling = keys[i]
+		}
+		if ceiling, _, ok := tm.Ceiling(probe); ceiling != expectedCeiling || ok != expectedCeilingOk {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", ceiling, ok, expectedCeiling, expectedCeilingOk)
+		}
+
+		if i < len(keys) && keys[i] == probe {
+			i++
+		}
+		expectedFloor, expectedFloorOk := 0, i > 0
+		if expectedFloorOk {
+			expectedFloor = keys[i-1]
+		}
+		if floor, _, ok := tm.Floor(probe); floor != expectedFloor || ok != expectedFloorOk {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", floor, ok, expectedFloor, expectedFloorOk)
+		}
+	}
+
+	for _, bounds := range [][2]int{{100, 200}, {-10, 5}, {250, 250}, {400, 600}, {300, 200}} {
+		expected := make([]int, 0)
+		for _, key := range keys {
+			if key >= bounds[0] && key <= bounds[1] {
+				expected = append(expected, key)
+			}
+		}
+		got := make([]int, 0)
+		tm.Range(bounds[0], bounds[1], func(i int, key int, val int) bool {
+			if i != len(got) {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", i, len(got))
+			}
+			got = append(got, key)
+			return true
+		})
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", got, expected)
+		}
+	}
+
+	// Deleting every key should leave an empty TreeMap
+	for _, key := range keys {
+		tm.Delete(key)
+	}
+	if _, _, ok := tm.Min(); ok || tm.Len() != 0 {
+		t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", ok, tm.Len(), false, 0)
+	}
+}
+
+func TestTrie(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	alphabet := []rune("abé日")
+	randomKey := func() string {
+		key := make([]rune, r.Intn(5))
+		for i := range key {
+			key[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return string(key)
+	}
+
+	trie := structs.NewTrie[int]()
+	model := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		key := randomKey()
+		_, exists := model[key]
+		if r.Intn(3) == 0 {
+			if deleted := trie.Delete(key); deleted != exists {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", deleted, exists)
+			}
+			delete(model, key)
+		} else {
+			if added := trie.Set(key, i); added == exists {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", added, !exists)
+			}
+			model[key] = i
+		}
+	}
+
+	if trie.Len() != len(model) {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", trie.Len(), len(model))
+	}
+
+	for i := 0; i < 200; i++ {
+		s := randomKey()
+		prefix := string([]rune(s)[:r.Intn(len([]rune(s))+1)])
+
+		// Keys with the prefix are enumerated in rune order, which for UTF-8 is the same as byte order
+		expectedKeys := make([]string, 0)
+		for _, key := range maps.OrderedKeys(model) {
+			if strings.HasPrefix(key, prefix) {
+				expectedKeys = append(expectedKeys, key)
+			}
+		}
+		if keys := trie.KeysWithPrefix(prefix); !reflect.DeepEqual(keys, expectedKeys) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", keys, expectedKeys)
+		}
+		if hasPrefix := trie.HasPrefix(prefix); hasPrefix != (len(expectedKeys) > 0) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", hasPrefix, len(expectedKeys) > 0)
+		}
+
+		expectedLongest, expectedOk := "", false
+		for key := range model {
+			if strings.HasPrefix(s, key) && (!expectedOk || len(key) > len(expectedLongest)) {
+				expectedLongest, expectedOk = key, true
+			}
+		}
+		if longest, val, ok := trie.LongestPrefix(s); longest != expectedLongest || ok != expectedOk || val != model[longest] {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", longest, ok, expectedLongest, expectedOk)
+		}
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	for _, test := range []struct {
+		n   uint64
+		fpr float64
+	}{
+		{1000, 0.01},
+		{5000, 0.001},
+		{100, 0.1},
+	} {
+		bf := structs.NewBloomFilter(test.n, test.fpr)
+		cbf := structs.NewCountingBloomFilter(test.n, test.fpr)
+		for i := uint64(0); i < test.n; i++ {
+			bf.AddString(strconv.FormatUint(i, 10))
+			cbf.AddString(strconv.FormatUint(i, 10))
+		}
+
+		// Round-trip both filters through their binary forms
+		data, _ := bf.MarshalBinary()
+		bf = &structs.BloomFilter{}
+		if err := bf.UnmarshalBinary(data); err != nil {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", err, nil)
+		}
+		data, _ = cbf.MarshalBinary()
+		cbf = &structs.CountingBloomFilter{}
+		if err := cbf.UnmarshalBinary(data); err != nil {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", err, nil)
+		}
+
+		// There should never be false negatives
+		for i := uint64(0); i < test.n; i++ {
+			if !bf.ContainsString(strconv.FormatUint(i, 10)) || !cbf.ContainsString(strconv.FormatUint(i, 10)) {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", false, true)
+			}
+		}
+
+		// The false positive rate should be close to the one that was asked for
+		falsePositives, countingFalsePositives := 0, 0
+		trials := 20000
+		for i := 0; i < trials; i++ {
+			s := "x" + strconv.Itoa(i)
+			if bf.ContainsString(s) {
+				falsePositives++
+			}
+			if cbf.ContainsString(s) {
+				countingFalsePositives++
+			}
+		}
+		for _, fp := range []int{falsePositives, countingFalsePositives} {
+			if rate := float64(fp) / float64(trials); rate > test.fpr*2 {
+				t.Errorf("Got: \"%v\", expected: \"<= %v\"", rate, test.fpr*2)
+			}
+		}
+
+		// Removing every item from the CountingBloomFilter should empty it
+		for i := uint64(0); i < test.n; i++ {
+			cbf.RemoveString(strconv.FormatUint(i, 10))
+		}
+		if cbf.Count() != 0 || cbf.ContainsString("0") {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", cbf.Count(), cbf.ContainsString("0"), 0, false)
+		}
+	}
+
+	// Corrupt binary forms should return errors
+	data, _ := structs.NewBloomFilter(10, 0.1).MarshalBinary()
+	for _, corrupt := range [][]byte{nil, data[:10], data[:len(data)-1], append([]byte("XXXX"), data[4:]...)} {
+		if err := (&structs.BloomFilter{}).UnmarshalBinary(corrupt); err == nil {
+			t.Errorf("Got: \"%v\", expected: an error", err)
+		}
+	}
+	if err := (&structs.CountMinSketch{}).UnmarshalBinary(data); err == nil {
+		t.Errorf("Got: \"%v\", expected: an error", err)
+	}
+
+	// Headers with more hash functions than locations should return errors rather than overflowing
+	cbfData, _ := structs.NewCountingBloomFilter(10, 0.1).MarshalBinary()
+	for _, k := range []uint64{0, 1 << 63, math.MaxUint64} {
+		corruptBF := append([]byte(nil), data...)
+		binary.BigEndian.PutUint64(corruptBF[12:20], k)
+		if err := (&structs.BloomFilter{}).UnmarshalBinary(corruptBF); err == nil {
+			t.Errorf("Got: \"%v\", expected: an error", err)
+		}
+		corruptCBF := append([]byte(nil), cbfData...)
+		binary.BigEndian.PutUint64(corruptCBF[12:20], k)
+		if err := (&structs.CountingBloomFilter{}).UnmarshalBinary(corruptCBF); err == nil {
+			t.Errorf("Got: \"%v\", expected: an error", err)
+		}
+	}
+}
+
+func TestCountMinSketch(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	epsilon := 0.001
+	cms := structs.NewCountMinSketch(epsilon, 0.01)
+	counts := make(map[string]uint64)
+	for i := 0; i < 50000; i++ {
+		// Skew the distribution so that some keys are much more frequent than others
+		key := strconv.Itoa(int(r.ExpFloat64() * 100))
+		cms.AddString(key, 1)
+		counts[key]++
+	}
+
+	data, _ := cms.MarshalBinary()
+	loaded := &structs.CountMinSketch{}
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Got: \"%v\", expected: \"%v\"", err, nil)
+	}
+	if err := loaded.Merge(cms); err != nil {
+		t.Fatalf("Got: \"%v\", expected: \"%v\"", err, nil)
+	}
+
+	bound := uint64(epsilon * float64(cms.Total()))
+	for key, count := range counts {
+		if estimate := cms.EstimateString(key); estimate < count || estimate > count+bound {
+			t.Errorf("Got: \"%v\", expected: \"%v <= x <= %v\"", estimate, count, count+bound)
+		}
+		if estimate := loaded.EstimateString(key); estimate < 2*count {
+			t.Errorf("Got: \"%v\", expected: \">= %v\"", estimate, 2*count)
+		}
+	}
+	if loaded.Total() != 2*cms.Total() {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", loaded.Total(), 2*cms.Total())
+	}
+	if err := loaded.Merge(structs.NewCountMinSketchSize(10, 2)); err == nil {
+		t.Errorf("Got: \"%v\", expected: an error", err)
+	}
+}
+
+func TestGraph(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	const nodes = 30
+	for _, directed := range []bool{true, false} {
+		g := structs.NewGraph[int, int](directed)
+		g.AddNode(numbers.Range(0, nodes-1, 1)...)
+		for i := 0; i < 60; i++ {
+			g.AddEdge(r.Intn(nodes), r.Intn(nodes), r.Intn(10))
+		}
+
+		// Compute all-pairs shortest paths and reachability using Floyd-Warshall
+		const inf = 1 << 30
+		dist := make([][]int, nodes)
+		for i := range dist {
+			dist[i] = make([]int, nodes)
+			for j := range dist[i] {
+				if w, ok := g.Weight(i, j); ok && (i != j || w < 0) {
+					dist[i][j] = w
+				} else if i != j {
+					dist[i][j] = inf
+				}
+			}
+		}
+		for k := 0; k < nodes; k++ {
+			for i := 0; i < nodes; i++ {
+				for j := 0; j < nodes; j++ {
+					if dist[i][k]+dist[k][j] < dist[i][j] {
+						dist[i][j] = dist[i][k] + dist[k][j]
+					}
+				}
+			}
+		}
+
+		for from := 0; from < nodes; from++ {
+			distances, _, err := g.Dijkstra(from)
+			if err != nil {
+				t.Fatalf("Got: \"%v\", expected: \"%v\"", err, nil)
+			}
+			for to := 0; to < nodes; to++ {
+				got, ok := distances[to]
+				if ok != (dist[from][to] < inf) || (ok && got != dist[from][to]) {
+					t.Errorf("Got: \"%v, %v\", expected: \"%v\"", got, ok, dist[from][to])
+				}
+
+				// The path returned by ShortestPath should have the same total weight
+				if path, d, err := g.ShortestPath(from, to); err == nil {
+					total := 0
+					for i := 1; i < len(path); i++ {
+						w, _ := g.Weight(path[i-1], path[i])
+						total += w
+					}
+					if total != d || d != dist[from][to] || path[0] != from || path[len(path)-1] != to {
+						t.Errorf("Got: \"%v, %v\", expected: \"%v\"", path, d, dist[from][to])
+					}
+				} else if !errors.Is(err, structs.ErrNoPath) || dist[from][to] < inf {
+					t.Errorf("Got: \"%v\", expected: \"%v\"", err, structs.ErrNoPath)
+				}
+			}
+
+			// DFS and BFS should both visit exactly the reachable nodes
+			for _, traverse := range []func(int, func(int, int) bool) error{g.DFS, g.BFS} {
+				visited := make(map[int]struct{})
+				_ = traverse(from, func(node int, depth int) bool {
+					visited[node] = struct{}{}
+					return true
+				})
+				for to := 0; to < nodes; to++ {
+					if _, ok := visited[to]; ok != (dist[from][to] < inf) {
+						t.Errorf("Got: \"%v\", expected: \"%v\"", ok, dist[from][to] < inf)
+					}
+				}
+			}
+		}
+
+		// Nodes are in the same strongly connected component iff they can reach each other
+		component := make(map[int]int)
+		for i, c := range g.StronglyConnectedComponents() {
+			for _, node := range c {
+				component[node] = i
+			}
+		}
+		for i := 0; i < nodes; i++ {
+			for j := 0; j < nodes; j++ {
+				mutual := dist[i][j] < inf && dist[j][i] < inf
+				if same := component[i] == component[j]; same != mutual {
+					t.Errorf("Got: \"%v\", expected: \"%v\"", same, mutual)
+				}
+			}
+		}
+
+		// Removing every edge should leave no edges
+		for _, edge := range g.Edges() {
+			if !g.RemoveEdge(edge.From, edge.To) {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", false, true)
+			}
+		}
+		if g.EdgeCount() != 0 || len(g.ConnectedComponents()) != nodes {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", g.EdgeCount(), len(g.ConnectedComponents()), 0, nodes)
+		}
+	}
+}
+
+func TestGraphTopologicalSort(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 50; i++ {
+		g := structs.NewGraph[int, int](true)
+		g.AddNode(numbers.Range(0, 19, 1)...)
+		// Only add edges from lower to higher nodes so that the Graph is acyclic
+		for j := 0; j < 40; j++ {
+			a, b := r.Intn(20), r.Intn(20)
+			if a < b {
+				g.AddEdge(a, b, 1)
+			}
+		}
+
+		order, err := g.TopologicalSort()
+		if err != nil {
+			t.Fatalf("Got: \"%v\", expected: \"%v\"", err, nil)
+		}
+		position := make(map[int]int)
+		for i, node := range order {
+			position[node] = i
+		}
+		for _, edge := range g.Edges() {
+			if position[edge.From] >= position[edge.To] {
+				t.Errorf("Got: \"%v\", expected: \"%v before %v\"", order, edge.From, edge.To)
+			}
+		}
+
+		// Adding a back edge along an existing path creates a cycle, which should be reported
+		edges := g.Edges()
+		if len(edges) == 0 {
+			continue
+		}
+		edge := edges[r.Intn(len(edges))]
+		g.AddEdge(edge.To, edge.From, 1)
+		_, err = g.TopologicalSort()
+		var cycleErr *structs.CycleError[int]
+		if !errors.As(err, &cycleErr) {
+			t.Fatalf("Got: \"%v\", expected: a CycleError", err)
+		}
+		cycle := cycleErr.Cycle
+		if len(cycle) < 2 || cycle[0] != cycle[len(cycle)-1] {
+			t.Errorf("Got: \"%v\", expected: a cycle", cycle)
+		}
+		for i := 1; i < len(cycle); i++ {
+			if !g.HasEdge(cycle[i-1], cycle[i]) {
+				t.Errorf("Got: \"%v\", expected: a cycle", cycle)
+			}
+		}
+	}
+
+	if _, err := structs.NewGraph[int, int](false).TopologicalSort(); !errors.Is(err, structs.ErrUndirected) {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", err, structs.ErrUndirected)
+	}
+}
+
+func TestDisjointSet(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	ds := structs.NewDisjointSet[int]()
+	// The model is the label of the set that each element is in. Labels are relabelled on each merge.
+	model := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		a, b := r.Intn(300), r.Intn(300)
+		for _, element := range []int{a, b} {
+			if _, ok := model[element]; !ok {
+				model[element] = element
+			}
+		}
+		expected := model[a] != model[b]
+		if merged := ds.Union(a, b); merged != expected {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", merged, expected)
+		}
+		if expected {
+			from, to := model[b], model[a]
+			for element, label := range model {
+				if label == from {
+					model[element] = to
+				}
+			}
+		}
+	}
+
+	labels := make(map[int]int)
+	for _, label := range model {
+		labels[label]++
+	}
+	if ds.Len() != len(model) || ds.Count() != len(labels) {
+		t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", ds.Len(), ds.Count(), len(model), len(labels))
+	}
+	for a := 0; a < 300; a += 7 {
+		for b := 0; b < 300; b += 11 {
+			_, okA := model[a]
+			_, okB := model[b]
+			expected := a == b || (okA && okB && model[a] == model[b])
+			if connected := ds.Connected(a, b); connected != expected {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", connected, expected)
+			}
+		}
+		expectedSize := 0
+		if label, ok := model[a]; ok {
+			expectedSize = labels[label]
+		}
+		if size := ds.Size(a); size != expectedSize {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", size, expectedSize)
+		}
+	}
+
+	total := 0
+	for _, set := range ds.Sets() {
+		total += len(set)
+		for _, element := range set {
+			if model[element] != model[set[0]] {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", model[element], model[set[0]])
+			}
+		}
+	}
+	if total != len(model) {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", total, len(model))
+	}
+}
+
+func TestIntervalTree(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	it := structs.NewIntervalTree[int, int]()
+	model := make([]structs.Interval[int, int], 0)
+	for i := 0; i < 3000; i++ {
+		low, high := r.Intn(1000), r.Intn(1000)
+		if low > high {
+			low, high = high, low
+		}
+		if r.Intn(3) == 0 && len(model) > 0 {
+			// Delete an existing interval, or one that probably doesn't exist
+			if r.Intn(2) == 0 {
+				existing := model[r.Intn(len(model))]
+				low, high = existing.Low, existing.High
+			}
+			expected := false
+			for j, interval := range model {
+				if interval.Low == low && interval.High == high {
+					model = append(model[:j], model[j+1:]...)
+					expected = true
+					break
+				}
+			}
+			if deleted := it.Delete(low, high); deleted != expected {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", deleted, expected)
+			}
+		} else {
+			it.Insert(low, high, i)
+			model = append(model, structs.Interval[int, int]{Low: low, High: high, Value: i})
+		}
+	}
+
+	if it.Len() != len(model) {
+		t.Fatalf("Got: \"%v\", expected: \"%v\"", it.Len(), len(model))
+	}
+	sort.SliceStable(model, func(i, j int) bool {
+		if model[i].Low != model[j].Low {
+			return model[i].Low < model[j].Low
+		}
+		return model[i].High < model[j].High
+	})
+	if got := it.Intervals(); !reflect.DeepEqual(got, model) {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", got, model)
+	}
+
+	for _, bounds := range [][2]int{{100, 200}, {-10, 5}, {250, 250}, {990, 2000}, {300, 200}, {-5, -1}} {
+		expected := make([]structs.Interval[int, int], 0)
+		for _, interval := range model {
+			if interval.Overlaps(bounds[0], bounds[1]) || interval.Overlaps(bounds[1], bounds[0]) {
+				expected = append(expected, interval)
+			}
+		}
+		if got := it.Overlapping(bounds[0], bounds[1]); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", got, expected)
+		}
+		if overlaps := it.Overlaps(bounds[0], bounds[1]); overlaps != (len(expected) > 0) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", overlaps, len(expected) > 0)
+		}
+	}
+
+	for point := 0; point < 1000; point += 37 {
+		expected := make([]structs.Interval[int, int], 0)
+		for _, interval := range model {
+			if interval.Contains(point) {
+				expected = append(expected, interval)
+			}
+		}
+		if got := it.Stab(point); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", got, expected)
+		}
+	}
+
+	for _, interval := range model {
+		it.Delete(interval.Low, interval.High)
+	}
+	if it.Len() != 0 || it.Overlaps(0, 1000) {
+		t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", it.Len(), it.Overlaps(0, 1000), 0, false)
+	}
+}
+
+func TestPersistentVector(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	type version struct {
+		vector *structs.PersistentVector[int]
+		model  []int
+	}
+	versions := []version{{&structs.PersistentVector[int]{}, []int{}}}
+	for i := 0; i < 3000; i++ {
+		current := versions[len(versions)-1]
+		if r.Intn(10) == 0 {
+			// Branch off from an older version
+			current = versions[r.Intn(len(versions))]
+		}
+
+		next := version{model: append([]int{}, current.model...)}
+		switch op := r.Intn(10); {
+		case op < 5:
+			values := make([]int, r.Intn(70))
+			for j := range values {
+				values[j] = r.Int()
+			}
+			next.vector = current.vector.Append(values...)
+			next.model = append(next.model, values...)
+		case op < 8 && len(current.model) > 0:
+			j, val := r.Intn(len(current.model)), r.Int()
+			next.vector = current.vector.Set(j, val)
+			next.model[j] = val
+		default:
+			var val int
+			var ok bool
+			next.vector, val, ok = current.vector.Pop()
+			expectedOk := len(current.model) > 0
+			if ok != expectedOk || (ok && val != current.model[len(current.model)-1]) {
+				t.Errorf("Got: \"%v, %v\", expected: \"%v\"", val, ok, expectedOk)
+			}
+			if expectedOk {
+				next.model = next.model[:len(next.model)-1]
+			}
+		}
+		versions = append(versions, next)
+	}
+
+	// Every version should be unaffected by the versions that were derived from it
+	for _, v := range versions {
+		if v.vector.Len() != len(v.model) {
+			t.Fatalf("Got: \"%v\", expected: \"%v\"", v.vector.Len(), len(v.model))
+		}
+		if got := v.vector.Values(); !reflect.DeepEqual(got, v.model) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", got, v.model)
+		}
+		for i := 0; i < len(v.model); i += 17 {
+			if got := v.vector.At(i); got != v.model[i] {
+				t.Errorf("Got: \"%v\", expected: \"%v\"", got, v.model[i])
+			}
+		}
+	}
+
+	// Popping every value from a large vector should shrink the trie back down
+	vector := structs.NewPersistentVector[int]()
+	for i := 0; i < 40000; i++ {
+		vector = vector.Append(i)
+	}
+	for i := 39999; i >= 0; i-- {
+		var val int
+		if vector, val, _ = vector.Pop(); val != i || vector.Len() != i {
+			t.Fatalf("Got: \"%v, %v\", expected: \"%v, %v\"", val, vector.Len(), i, i)
+		}
+	}
+}
+
+func TestPersistentMap(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	type version struct {
+		m     *structs.PersistentMap[int, int]
+		model map[int]int
+	}
+	versions := []version{{structs.NewPersistentMap[int, int](), map[int]int{}}}
+	for i := 0; i < 5000; i++ {
+		current := versions[len(versions)-1]
+		if r.Intn(10) == 0 {
+			current = versions[r.Intn(len(versions))]
+		}
+
+		next := version{model: make(map[int]int, len(current.model)+1)}
+		for key, val := range current.model {
+			next.model[key] = val
+		}
+		key := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			next.m = current.m.Delete(key)
+			delete(next.model, key)
+		} else {
+			next.m = current.m.Set(key, i)
+			next.model[key] = i
+		}
+		versions = append(versions, next)
+	}
+
+	for _, v := range versions {
+		if v.m.Len() != len(v.model) {
+			t.Fatalf("Got: \"%v\", expected: \"%v\"", v.m.Len(), len(v.model))
+		}
+		if got := v.m.ToMap(); !reflect.DeepEqual(got, v.model) {
+			t.Errorf("Got: \"%v\", expected: \"%v\"", got, v.model)
+		}
+		for key := 0; key < 1000; key += 13 {
+			expectedVal, expectedOk := v.model[key]
+			if val, ok := v.m.Get(key); val != expectedVal || ok != expectedOk {
+				t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", val, ok, expectedVal, expectedOk)
+			}
+		}
+	}
+
+	// The zero value is an empty PersistentMap that is ready to use
+	var zero structs.PersistentMap[string, int]
+	zero.Range(func(i int, key string, val int) bool {
+		t.Errorf("Got: \"%v\" from an empty PersistentMap", key)
+		return true
+	})
+	if deleted := zero.Delete("a"); deleted != &zero {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", deleted, &zero)
+	}
+	set := zero.Set("a", 1).Set("b", 2)
+	if got, expected := set.ToMap(), map[string]int{"a": 1, "b": 2}; !reflect.DeepEqual(got, expected) || zero.Len() != 0 {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", got, expected)
+	}
+
+	// Struct keys are hashed by their contents
+	type point struct{ x, y int }
+	points := structs.NewPersistentMapFrom(map[point]string{{1, 2}: "a", {2, 1}: "b"})
+	if val, ok := points.Get(point{1, 2}); val != "a" || !ok {
+		t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", val, ok, "a", true)
+	}
+	if deleted := points.Delete(point{3, 3}); deleted != points {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", deleted, points)
+	}
+
+	// Fields are hashed one after another, so splitting the same string between two fields produces the same hash. This
+	// forces the keys into collision nodes.
+	type split struct{ a, b string }
+	key := func(i int) split { return split{strings.Repeat("x", i), strings.Repeat("x", 50-i)} }
+	collisions := structs.NewPersistentMap[split, int]()
+	for i := 0; i <= 50; i++ {
+		collisions = collisions.Set(key(i), i)
+	}
+	for i := 0; i <= 50; i += 2 {
+		collisions = collisions.Delete(key(i))
+	}
+	if collisions.Len() != 25 {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", collisions.Len(), 25)
+	}
+	for i := 0; i <= 50; i++ {
+		expectedOk := i%2 == 1
+		if val, ok := collisions.Get(key(i)); ok != expectedOk || (ok && val != i) {
+			t.Errorf("Got: \"%v, %v\", expected: \"%v, %v\"", val, ok, i, expectedOk)
+		}
+	}
+	for i := 1; i <= 50; i += 2 {
+		collisions = collisions.Delete(key(i))
+	}
+	if collisions.Len() != 0 || len(collisions.Keys()) != 0 {
+		t.Errorf("Got: \"%v\", expected: \"%v\"", collisions.Keys(), []split{})
+	}
+}